@@ -0,0 +1,17 @@
+data "vyos_config" "address_groups" {
+  path = "firewall group address-group"
+}
+
+resource "vyos_config" "allow_groups" {
+  for_each = toset(data.vyos_config.address_groups.keys)
+
+  path = "firewall name WAN_LOCAL rule ${index(data.vyos_config.address_groups.keys, each.key) + 100}"
+  value = jsonencode({
+    action = "accept"
+    source = {
+      group = {
+        address-group = each.key
+      }
+    }
+  })
+}
@@ -0,0 +1,15 @@
+data "vyos_show" "interfaces" {
+  command = "show interfaces"
+}
+
+data "vyos_show" "version" {
+  command = "show version"
+}
+
+output "wan_addresses" {
+  value = one([for iface in data.vyos_show.interfaces.interfaces : iface.addresses if iface.name == "eth0"])
+}
+
+output "vyos_version" {
+  value = data.vyos_show.version.version.version
+}
@@ -0,0 +1,5 @@
+data "vyos_version" "router" {}
+
+output "firewall_layout" {
+  value = data.vyos_version.router.major > 1 || data.vyos_version.router.minor >= 4 ? "firewall ipv4 name" : "firewall name"
+}
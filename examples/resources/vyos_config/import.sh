@@ -0,0 +1,3 @@
+# The import ID is the configuration path, quoting components which contain spaces
+terraform import vyos_config.test "firewall name WAN_LOCAL"
+terraform import vyos_config.banner "system login banner pre-login 'Authorised access only'"
@@ -0,0 +1,7 @@
+resource "vyos_config_commands" "wan" {
+  commands = [
+    "set interfaces ethernet eth0 address 192.0.2.10/24",
+    "set interfaces ethernet eth0 description 'WAN uplink'",
+    "set protocols static route 0.0.0.0/0 next-hop 192.0.2.1",
+  ]
+}
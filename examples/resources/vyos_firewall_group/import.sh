@@ -0,0 +1,2 @@
+# The import ID is the type and name of the group separated by a slash
+terraform import vyos_firewall_group.admin network/ADMIN
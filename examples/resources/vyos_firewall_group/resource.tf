@@ -0,0 +1,6 @@
+resource "vyos_firewall_group" "admin" {
+  name        = "ADMIN"
+  type        = "network"
+  description = "Administrator networks"
+  members     = ["192.0.2.0/24", "198.51.100.0/24"]
+}
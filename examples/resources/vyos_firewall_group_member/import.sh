@@ -0,0 +1,2 @@
+# The import ID is the type, group and member separated by slashes
+terraform import vyos_firewall_group_member.office network/ADMIN/192.0.2.0/24
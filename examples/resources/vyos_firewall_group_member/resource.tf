@@ -0,0 +1,5 @@
+resource "vyos_firewall_group_member" "office" {
+  group  = "ADMIN"
+  type   = "network"
+  member = "192.0.2.0/24"
+}
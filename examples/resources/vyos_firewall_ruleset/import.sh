@@ -0,0 +1,3 @@
+# The import ID is the name of the ruleset, prefixed with ipv6/ for IPv6 rulesets
+terraform import vyos_firewall_ruleset.wan_local WAN_LOCAL
+terraform import vyos_firewall_ruleset.wan_local_v6 ipv6/WAN_LOCAL
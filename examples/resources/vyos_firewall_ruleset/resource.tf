@@ -0,0 +1,25 @@
+resource "vyos_firewall_ruleset" "wan_local" {
+  name           = "WAN_LOCAL"
+  default_action = "drop"
+  description    = "Traffic from the internet to the router"
+
+  rule = {
+    10 = {
+      action = "accept"
+      state  = ["established", "related"]
+    }
+    20 = {
+      action   = "accept"
+      protocol = "tcp"
+      destination = {
+        port = "22"
+      }
+      source = {
+        group = {
+          network_group = "ADMIN"
+        }
+      }
+      log = true
+    }
+  }
+}
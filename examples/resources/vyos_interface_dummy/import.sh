@@ -0,0 +1,2 @@
+# The import ID is the name of the interface
+terraform import vyos_interface_dummy.loopback dum0
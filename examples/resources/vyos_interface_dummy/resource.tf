@@ -0,0 +1,5 @@
+resource "vyos_interface_dummy" "loopback" {
+  name        = "dum0"
+  address     = ["192.0.2.1/32", "2001:db8::1/128"]
+  description = "Router ID"
+}
@@ -0,0 +1,2 @@
+# The import ID is the type and number of the rule separated by a slash
+terraform import vyos_nat_rule.masquerade source/100
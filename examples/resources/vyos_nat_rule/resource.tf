@@ -0,0 +1,27 @@
+resource "vyos_nat_rule" "masquerade" {
+  type               = "source"
+  rule               = 100
+  description        = "Masquerade the LAN"
+  outbound_interface = "eth0"
+
+  source = {
+    address = "192.0.2.0/24"
+  }
+  translation = {
+    address = "masquerade"
+  }
+}
+
+resource "vyos_nat_rule" "https" {
+  type              = "destination"
+  rule              = 10
+  inbound_interface = "eth0"
+  protocol          = "tcp"
+
+  destination = {
+    port = "443"
+  }
+  translation = {
+    address = "192.0.2.10"
+  }
+}
@@ -0,0 +1,2 @@
+# The import ID is the configuration path
+terraform import vyos_service_ssh.ssh "service ssh"
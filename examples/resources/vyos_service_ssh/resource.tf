@@ -0,0 +1,9 @@
+resource "vyos_service_ssh" "ssh" {
+  port                            = 22
+  listen_address                  = ["192.0.2.1"]
+  disable_password_authentication = true
+
+  dynamic_protection = {
+    threshold = 30
+  }
+}
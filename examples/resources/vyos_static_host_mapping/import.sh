@@ -0,0 +1,2 @@
+# The import ID is the host name
+terraform import vyos_static_host_mapping.nas nas.example.com
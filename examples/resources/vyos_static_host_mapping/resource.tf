@@ -0,0 +1,5 @@
+resource "vyos_static_host_mapping" "nas" {
+  name  = "nas.example.com"
+  inet  = ["192.0.2.20"]
+  alias = ["nas"]
+}
@@ -0,0 +1,231 @@
+// Package export writes the configuration of a live router as Terraform
+// configuration, one vyos_config resource per subtree with a matching import
+// block, so an existing router can be brought under Terraform.
+package export
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/TGNThump/terraform-provider-vyos/internal/transport"
+	"github.com/TGNThump/terraform-provider-vyos/internal/vyos"
+)
+
+// DefaultSplitDepth is the path depth configuration is split into resources
+// at, such as `interfaces ethernet`.
+const DefaultSplitDepth = 2
+
+// Options are the settings of an export.
+type Options struct {
+	Endpoint string
+	ApiKey   string
+	Tls      transport.TlsOptions
+	// ProxyUrl is an http, https or socks5 proxy to reach the router
+	// through, empty to use the proxy set by the HTTPS_PROXY environment
+	// variables.
+	ProxyUrl   string
+	SplitDepth int
+	OutputDir  string
+}
+
+// Resource is a vyos_config resource to write.
+type Resource struct {
+	Label string
+	Path  []string
+	Value any
+}
+
+// Main runs the export subcommand with its command line arguments, writing
+// progress to stdout.
+func Main(ctx context.Context, args []string, stdout io.Writer) error {
+	options := Options{}
+
+	insecure := false
+	if value := os.Getenv("VYOS_INSECURE"); value != "" {
+		var err error
+		if insecure, err = strconv.ParseBool(value); err != nil {
+			return fmt.Errorf("invalid VYOS_INSECURE %q: %w", value, err)
+		}
+	}
+
+	flags := flag.NewFlagSet("export", flag.ContinueOnError)
+	flags.SetOutput(stdout)
+	flags.Usage = func() {
+		fmt.Fprintln(stdout, "Usage: terraform-provider-vyos export [options]")
+		fmt.Fprintln(stdout)
+		fmt.Fprintln(stdout, "Writes the configuration of a router as vyos_config resources with matching import blocks.")
+		fmt.Fprintln(stdout)
+		flags.PrintDefaults()
+	}
+	flags.StringVar(&options.Endpoint, "endpoint", os.Getenv("VYOS_ENDPOINT"), "endpoint of the VyOS HTTP API, defaults to VYOS_ENDPOINT")
+	flags.StringVar(&options.ApiKey, "api-key", os.Getenv("VYOS_API_KEY"), "API key, defaults to VYOS_API_KEY")
+	flags.BoolVar(&options.Tls.Insecure, "insecure", insecure, "skip verifying the TLS certificate of the router, defaults to VYOS_INSECURE")
+	flags.StringVar(&options.Tls.CaCertFile, "ca-cert-file", os.Getenv("VYOS_CA_CERT_FILE"), "PEM file of CA certificates to verify the router with, defaults to VYOS_CA_CERT_FILE")
+	flags.StringVar(&options.Tls.CaCertPem, "ca-cert-pem", os.Getenv("VYOS_CA_CERT_PEM"), "PEM CA certificates to verify the router with, defaults to VYOS_CA_CERT_PEM")
+	flags.StringVar(&options.Tls.ClientCert, "client-cert", os.Getenv("VYOS_CLIENT_CERT"), "client certificate as PEM or a PEM file, defaults to VYOS_CLIENT_CERT")
+	flags.StringVar(&options.Tls.ClientKey, "client-key", os.Getenv("VYOS_CLIENT_KEY"), "key of the client certificate as PEM or a PEM file, defaults to VYOS_CLIENT_KEY")
+	flags.StringVar(&options.Tls.ServerName, "tls-server-name", os.Getenv("VYOS_TLS_SERVER_NAME"), "name to verify the certificate of the router against, defaults to VYOS_TLS_SERVER_NAME")
+	flags.StringVar(&options.ProxyUrl, "proxy-url", os.Getenv("VYOS_PROXY_URL"), "http, https or socks5 proxy to reach the router through, defaults to VYOS_PROXY_URL")
+	flags.IntVar(&options.SplitDepth, "split-depth", DefaultSplitDepth, "path depth to split the configuration into resources at")
+	flags.StringVar(&options.OutputDir, "output", ".", "directory to write the .tf files to")
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() > 0 {
+		return fmt.Errorf("unexpected argument %q", flags.Arg(0))
+	}
+
+	files, resources, err := Run(ctx, options)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(stdout, "Wrote %d resources to %d files in %s\n", resources, len(files), options.OutputDir)
+	for _, file := range files {
+		fmt.Fprintln(stdout, "  "+file)
+	}
+	return nil
+}
+
+// Run exports the configuration of the router, returning the files written
+// and the number of resources in them.
+func Run(ctx context.Context, options Options) ([]string, int, error) {
+	if options.Endpoint == "" || options.ApiKey == "" {
+		return nil, 0, errors.New("the endpoint and API key must be set, with -endpoint and -api-key or VYOS_ENDPOINT and VYOS_API_KEY")
+	}
+	if options.SplitDepth < 1 {
+		return nil, 0, errors.New("the split depth must be at least 1")
+	}
+
+	httpClient, err := newHttpClient(options)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	vyosConfig := vyos.New(vyos.NewApiBackend(httpClient, options.Endpoint, options.ApiKey), true, "")
+	config, err := vyosConfig.GetFullConfig(ctx)
+	if err != nil {
+		return nil, 0, fmt.Errorf("unable to read the router configuration: %w", err)
+	}
+
+	resources := Split(*config, options.SplitDepth)
+
+	// Resources are written to a file per top level node, such as
+	// interfaces.tf.
+	byFile := map[string][]Resource{}
+	for _, resource := range resources {
+		file := resourceLabel(resource.Path[:1]) + ".tf"
+		byFile[file] = append(byFile[file], resource)
+	}
+
+	files := make([]string, 0, len(byFile))
+	for file := range byFile {
+		files = append(files, file)
+	}
+	sort.Strings(files)
+
+	if err := os.MkdirAll(options.OutputDir, 0o755); err != nil {
+		return nil, 0, err
+	}
+	for _, file := range files {
+		if err := os.WriteFile(filepath.Join(options.OutputDir, file), []byte(Format(byFile[file])), 0o644); err != nil {
+			return nil, 0, err
+		}
+	}
+
+	return files, len(resources), nil
+}
+
+// Split splits a configuration tree into resources at depth, or at the leaf
+// when a branch ends before it, sorted by path.
+func Split(config map[string]any, depth int) []Resource {
+	var resources []Resource
+	split(&resources, config, []string{}, depth)
+
+	sort.Slice(resources, func(i, j int) bool {
+		return strings.Join(resources[i].Path, "\x00") < strings.Join(resources[j].Path, "\x00")
+	})
+
+	labels := map[string]int{}
+	for i := range resources {
+		label := resourceLabel(resources[i].Path)
+		labels[label]++
+		if labels[label] > 1 {
+			label = fmt.Sprintf("%s_%d", label, labels[label])
+		}
+		resources[i].Label = label
+	}
+	return resources
+}
+
+func split(resources *[]Resource, tree map[string]any, path []string, depth int) {
+	for key, child := range tree {
+		childPath := append(append([]string{}, path...), key)
+
+		if subtree, ok := child.(map[string]any); ok && len(subtree) > 0 && len(childPath) < depth {
+			split(resources, subtree, childPath, depth)
+			continue
+		}
+		*resources = append(*resources, Resource{Path: childPath, Value: child})
+	}
+}
+
+// Format writes resources as vyos_config resources, each followed by the
+// import block adopting the existing configuration.
+func Format(resources []Resource) string {
+	var builder strings.Builder
+	for i, resource := range resources {
+		if i > 0 {
+			builder.WriteString("\n")
+		}
+
+		path := hclString(vyos.FormatPath(resource.Path))
+		fmt.Fprintf(&builder, "resource \"vyos_config\" %q {\n", resource.Label)
+		attributes := [][2]string{{"path", path}}
+		// A valueless node, such as `disable`, is set by leaving out value.
+		if tree, ok := resource.Value.(map[string]any); !ok || len(tree) > 0 {
+			attributes = append(attributes, [2]string{"value", "jsonencode(" + hclValue(resource.Value, "  ") + ")"})
+		}
+		writeAttributes(&builder, attributes, "  ")
+		builder.WriteString("}\n\n")
+
+		builder.WriteString("import {\n")
+		fmt.Fprintf(&builder, "  to = vyos_config.%s\n", resource.Label)
+		fmt.Fprintf(&builder, "  id = %s\n", path)
+		builder.WriteString("}\n")
+	}
+	return builder.String()
+}
+
+// newHttpClient builds the HTTP client the same way the provider does.
+func newHttpClient(options Options) (*http.Client, error) {
+	tlsConfig, err := options.Tls.TlsConfig()
+	if err != nil {
+		return nil, fmt.Errorf("invalid TLS settings: %w", err)
+	}
+
+	var proxyUrl *url.URL
+	if options.ProxyUrl != "" {
+		if proxyUrl, err = transport.ParseProxyUrl(options.ProxyUrl); err != nil {
+			return nil, fmt.Errorf("invalid proxy URL: %w", err)
+		}
+	}
+
+	return transport.NewHttpClient(transport.HttpClientOptions{
+		TlsConfig:      tlsConfig,
+		ConnectTimeout: transport.DefaultConnectTimeout,
+		RequestTimeout: transport.DefaultRequestTimeout,
+		ProxyUrl:       proxyUrl,
+	}), nil
+}
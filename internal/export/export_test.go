@@ -0,0 +1,161 @@
+package export
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/TGNThump/terraform-provider-vyos/internal/vyostest"
+)
+
+func TestSplit(t *testing.T) {
+	config := map[string]any{
+		"interfaces": map[string]any{
+			"ethernet": map[string]any{
+				"eth0": map[string]any{"address": "192.0.2.10/24"},
+				"eth1": map[string]any{"disable": map[string]any{}},
+			},
+		},
+		"system": map[string]any{
+			"host-name": "vyos",
+			"ipv6":      map[string]any{"disable-forwarding": map[string]any{}},
+		},
+	}
+
+	resources := Split(config, 2)
+	expected := []Resource{
+		{Label: "interfaces_ethernet", Path: []string{"interfaces", "ethernet"}, Value: config["interfaces"].(map[string]any)["ethernet"]},
+		{Label: "system_host_name", Path: []string{"system", "host-name"}, Value: "vyos"},
+		{Label: "system_ipv6", Path: []string{"system", "ipv6"}, Value: map[string]any{"disable-forwarding": map[string]any{}}},
+	}
+	if !reflect.DeepEqual(resources, expected) {
+		t.Errorf("unexpected resources: %#v", resources)
+	}
+
+	resources = Split(config, 3)
+	if len(resources) != 4 || resources[1].Label != "interfaces_ethernet_eth1" {
+		t.Errorf("unexpected resources: %#v", resources)
+	}
+}
+
+func TestFormat(t *testing.T) {
+	resources := []Resource{
+		{Label: "firewall_name_WAN_LOCAL", Path: []string{"firewall", "name", "WAN_LOCAL"}, Value: map[string]any{
+			"default-action": "drop",
+			"rule": map[string]any{
+				"10": map[string]any{"action": "accept", "state": map[string]any{"established": "enable"}},
+			},
+		}},
+		{Label: "system_login_banner_pre_login", Path: []string{"system", "login", "banner", "pre-login"}, Value: "Authorised ${user} only"},
+		{Label: "system_ipv6_disable_forwarding", Path: []string{"system", "ipv6", "disable-forwarding"}, Value: map[string]any{}},
+	}
+
+	expected := `resource "vyos_config" "firewall_name_WAN_LOCAL" {
+  path = "firewall name WAN_LOCAL"
+  value = jsonencode({
+    default-action = "drop"
+    rule = {
+      "10" = {
+        action = "accept"
+        state = {
+          established = "enable"
+        }
+      }
+    }
+  })
+}
+
+import {
+  to = vyos_config.firewall_name_WAN_LOCAL
+  id = "firewall name WAN_LOCAL"
+}
+
+resource "vyos_config" "system_login_banner_pre_login" {
+  path  = "system login banner pre-login"
+  value = jsonencode("Authorised $${user} only")
+}
+
+import {
+  to = vyos_config.system_login_banner_pre_login
+  id = "system login banner pre-login"
+}
+
+resource "vyos_config" "system_ipv6_disable_forwarding" {
+  path = "system ipv6 disable-forwarding"
+}
+
+import {
+  to = vyos_config.system_ipv6_disable_forwarding
+  id = "system ipv6 disable-forwarding"
+}
+`
+	if text := Format(resources); text != expected {
+		t.Errorf("unexpected configuration:\n%s", text)
+	}
+}
+
+func TestExport(t *testing.T) {
+	server := vyostest.NewServer("key")
+	t.Cleanup(server.Close)
+	server.SetConfig(map[string]any{
+		"interfaces": map[string]any{
+			"ethernet": map[string]any{"eth0": map[string]any{"address": []any{"192.0.2.10/24", "2001:db8::10/64"}}},
+		},
+		"system": map[string]any{"host-name": "vyos"},
+	})
+
+	output := t.TempDir()
+	var stdout bytes.Buffer
+	err := Main(context.Background(), []string{"-endpoint", server.URL, "-api-key", "key", "-split-depth", "3", "-output", output}, &stdout)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !strings.HasPrefix(stdout.String(), "Wrote 2 resources to 2 files") {
+		t.Errorf("unexpected output: %s", stdout.String())
+	}
+
+	interfaces, err := os.ReadFile(filepath.Join(output, "interfaces.tf"))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !strings.Contains(string(interfaces), `address = ["192.0.2.10/24", "2001:db8::10/64"]`) ||
+		!strings.Contains(string(interfaces), `id = "interfaces ethernet eth0"`) {
+		t.Errorf("unexpected interfaces.tf:\n%s", interfaces)
+	}
+
+	if _, err := os.Stat(filepath.Join(output, "system.tf")); err != nil {
+		t.Errorf("expected system.tf: %s", err)
+	}
+
+	if err := Main(context.Background(), []string{"-endpoint", server.URL, "-api-key", "wrong", "-output", output}, &stdout); err == nil {
+		t.Error("expected an error with the wrong API key")
+	}
+}
+
+func TestExportSettingsErrors(t *testing.T) {
+	server := vyostest.NewServer("key")
+	t.Cleanup(server.Close)
+
+	cases := map[string][]string{
+		"proxy URL":   {"-proxy-url", "ftp://bastion"},
+		"CA file":     {"-ca-cert-file", filepath.Join(t.TempDir(), "missing.pem")},
+		"client cert": {"-client-cert", "client.pem"},
+	}
+
+	for name, args := range cases {
+		args = append([]string{"-endpoint", server.URL, "-api-key", "key", "-output", t.TempDir()}, args...)
+		if err := Main(context.Background(), args, &bytes.Buffer{}); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+
+	t.Setenv("VYOS_INSECURE", "yes")
+	if err := Main(context.Background(), []string{"-endpoint", server.URL, "-api-key", "key"}, &bytes.Buffer{}); err == nil ||
+		!strings.Contains(err.Error(), "VYOS_INSECURE") {
+		t.Errorf("expected an error for an invalid VYOS_INSECURE, got %v", err)
+	}
+}
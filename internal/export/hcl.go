@@ -0,0 +1,105 @@
+package export
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+var identifierPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_-]*$`)
+
+// hclValue writes a configuration tree as an HCL expression, as passed to
+// jsonencode, indented by indent.
+func hclValue(value any, indent string) string {
+	switch v := value.(type) {
+	case string:
+		return hclString(v)
+	case []any:
+		items := make([]string, len(v))
+		for i, item := range v {
+			items[i] = hclValue(item, indent)
+		}
+		return "[" + strings.Join(items, ", ") + "]"
+	case map[string]any:
+		if len(v) == 0 {
+			return "{}"
+		}
+
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		lines := make([][2]string, len(keys))
+		for i, key := range keys {
+			lines[i] = [2]string{hclKey(key), hclValue(v[key], indent+"  ")}
+		}
+
+		var builder strings.Builder
+		builder.WriteString("{\n")
+		writeAttributes(&builder, lines, indent+"  ")
+		builder.WriteString(indent + "}")
+		return builder.String()
+	}
+	return hclString(fmt.Sprint(value))
+}
+
+// hclKey writes an object key, quoting keys which are not identifiers such
+// as rule numbers or addresses.
+func hclKey(key string) string {
+	if identifierPattern.MatchString(key) {
+		return key
+	}
+	return hclString(key)
+}
+
+// hclString writes a quoted HCL string, escaping template sequences.
+func hclString(value string) string {
+	replacer := strings.NewReplacer(
+		`\`, `\\`,
+		`"`, `\"`,
+		"\n", `\n`,
+		"\r", `\r`,
+		"\t", `\t`,
+		"${", "$${",
+		"%{", "%%{",
+	)
+	return `"` + replacer.Replace(value) + `"`
+}
+
+// writeAttributes writes name = value lines, aligning the equals signs of
+// consecutive single line values as terraform fmt does.
+func writeAttributes(builder *strings.Builder, lines [][2]string, indent string) {
+	for start := 0; start < len(lines); {
+		end := start
+		width := 0
+		for end < len(lines) && !strings.Contains(lines[end][1], "\n") {
+			if len(lines[end][0]) > width {
+				width = len(lines[end][0])
+			}
+			end++
+		}
+		if end == start {
+			end++
+		}
+
+		for _, line := range lines[start:end] {
+			fmt.Fprintf(builder, "%s%-*s = %s\n", indent, width, line[0], line[1])
+		}
+		start = end
+	}
+}
+
+var labelPattern = regexp.MustCompile(`[^A-Za-z0-9_]+`)
+
+// resourceLabel turns a configuration path into a resource name, such as
+// `interfaces_ethernet_eth0`.
+func resourceLabel(path []string) string {
+	label := strings.Trim(labelPattern.ReplaceAllString(strings.Join(path, "_"), "_"), "_")
+	if label == "" || (label[0] >= '0' && label[0] <= '9') {
+		label = "_" + label
+	}
+	return label
+}
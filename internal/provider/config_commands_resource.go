@@ -0,0 +1,358 @@
+package provider
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/TGNThump/terraform-provider-vyos/internal/vyos"
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces
+var _ resource.Resource = &ConfigCommandsResource{}
+var _ resource.ResourceWithConfigure = &ConfigCommandsResource{}
+
+func NewConfigCommandsResource() resource.Resource {
+	return &ConfigCommandsResource{}
+}
+
+// ConfigCommandsResource defines the resource implementation.
+type ConfigCommandsResource struct {
+	vyosConfig *vyos.VyosConfig
+}
+
+// ConfigCommandsResourceModel describes the resource data model.
+type ConfigCommandsResourceModel struct {
+	Commands types.List   `tfsdk:"commands"`
+	Id       types.String `tfsdk:"id"`
+
+	Timeouts timeouts.Value `tfsdk:"timeouts"`
+}
+
+func (r *ConfigCommandsResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_config_commands"
+}
+
+func (r *ConfigCommandsResource) Schema(ctx context.Context, request resource.SchemaRequest, response *resource.SchemaResponse) {
+	response.Schema = schema.Schema{
+		MarkdownDescription: "Configuration given as VyOS CLI `set` commands. Only the nodes and values the commands set are " +
+			"managed, removing a command deletes what it set.",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Configuration identifier, the path every command starts with, or a hash of the commands when they share none",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"commands": schema.ListAttribute{
+				MarkdownDescription: "VyOS CLI `set` commands, such as `set interfaces ethernet eth0 address 10.0.0.1/24`. " +
+					"Components containing spaces are quoted as on the VyOS CLI.",
+				Required:    true,
+				ElementType: types.StringType,
+				Validators: []validator.List{
+					listvalidator.SizeAtLeast(1),
+					listvalidator.ValueStringsAre(setCommandValidator{}),
+				},
+			},
+		},
+
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Create: true,
+				Read:   true,
+				Update: true,
+				Delete: true,
+			}),
+		},
+	}
+}
+
+func (r *ConfigCommandsResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	vyosConfig, ok := req.ProviderData.(*vyos.VyosConfig)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *vyos.VyosConfig, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.vyosConfig = vyosConfig
+}
+
+func (r *ConfigCommandsResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data *ConfigCommandsResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	createTimeout, diags := data.Timeouts.Create(ctx, defaultConfigTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, createTimeout)
+	defer cancel()
+
+	commands, paths, diags := setCommands(ctx, data.Commands)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Info(ctx, fmt.Sprintf("Setting %d commands", len(paths)))
+
+	if err := r.apply(ctx, nil, paths); err != nil {
+		addVyosError(&resp.Diagnostics, path.Root("commands"), err)
+		return
+	}
+
+	data.Id = types.StringValue(commandsId(commands, paths))
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *ConfigCommandsResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data *ConfigCommandsResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	readTimeout, diags := data.Timeouts.Read(ctx, defaultConfigTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, readTimeout)
+	defer cancel()
+
+	commands, paths, diags := setCommands(ctx, data.Commands)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Commands whose node or value is gone from the router are dropped, so
+	// they show up as a change.
+	config, err := r.vyosConfig.Show(ctx, []string{})
+	if err != nil {
+		addVyosError(&resp.Diagnostics, path.Root("commands"), err)
+		return
+	}
+
+	present := []attr.Value{}
+	for i, components := range paths {
+		if vyos.ContainsPath(config, components) {
+			present = append(present, types.StringValue(commands[i]))
+		} else {
+			tflog.Info(ctx, "Command no longer applied: "+commands[i])
+		}
+	}
+
+	if len(present) == 0 {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	data.Commands, diags = types.ListValue(types.StringType, present)
+	resp.Diagnostics.Append(diags...)
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *ConfigCommandsResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan *ConfigCommandsResourceModel
+	var state *ConfigCommandsResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	updateTimeout, diags := plan.Timeouts.Update(ctx, defaultConfigTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, updateTimeout)
+	defer cancel()
+
+	_, paths, diags := setCommands(ctx, plan.Commands)
+	resp.Diagnostics.Append(diags...)
+	_, priorPaths, diags := setCommands(ctx, state.Commands)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Info(ctx, fmt.Sprintf("Updating from %d to %d commands", len(priorPaths), len(paths)))
+
+	if err := r.apply(ctx, priorPaths, paths); err != nil {
+		addVyosError(&resp.Diagnostics, path.Root("commands"), err)
+		return
+	}
+
+	// Save updated plan into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *ConfigCommandsResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data *ConfigCommandsResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	deleteTimeout, diags := data.Timeouts.Delete(ctx, defaultConfigTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, deleteTimeout)
+	defer cancel()
+
+	_, paths, diags := setCommands(ctx, data.Commands)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Info(ctx, fmt.Sprintf("Deleting %d commands", len(paths)))
+
+	if err := r.apply(ctx, paths, nil); err != nil {
+		addVyosError(&resp.Diagnostics, path.Root("commands"), err)
+		return
+	}
+}
+
+// apply commits the operations turning the configuration set by the prior
+// commands into the one set by commands, in a single commit.
+func (r *ConfigCommandsResource) apply(ctx context.Context, prior [][]string, commands [][]string) error {
+	current, err := r.vyosConfig.Show(ctx, []string{})
+	if err != nil {
+		return err
+	}
+
+	currentTree, _ := current.(map[string]any)
+	old := vyos.CommandTree(prior, current)
+	new := vyos.CommandTree(commands, current)
+
+	keys := []string{}
+	for key := range old {
+		keys = append(keys, key)
+	}
+	for key := range new {
+		if _, ok := old[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	// Each top level node is diffed on its own, as commands setting every
+	// leaf of the configuration must never delete the root.
+	var payload []map[string]any
+	for _, key := range keys {
+		payload = append(payload, vyos.Diff([]string{key}, old[key], new[key], currentTree[key])...)
+	}
+
+	tflog.Info(ctx, fmt.Sprintf("Computed %d operations for %d commands", len(payload), len(commands)), map[string]interface{}{
+		"operations": payload,
+	})
+
+	return r.vyosConfig.Configure(ctx, payload)
+}
+
+// setCommands returns the commands of a list attribute along with the paths
+// they set.
+func setCommands(ctx context.Context, list types.List) ([]string, [][]string, diag.Diagnostics) {
+	var commands []string
+	diags := list.ElementsAs(ctx, &commands, false)
+	if diags.HasError() {
+		return nil, nil, diags
+	}
+
+	paths := make([][]string, len(commands))
+	for i, command := range commands {
+		components, err := vyos.ParseSetCommand(command)
+		if err != nil {
+			diags.AddAttributeError(path.Root("commands").AtListIndex(i), "Invalid set command", err.Error())
+			continue
+		}
+		paths[i] = components
+	}
+	return commands, paths, diags
+}
+
+// commandsId returns the path every command starts with, or a hash of the
+// sorted commands when they share no path.
+func commandsId(commands []string, paths [][]string) string {
+	if prefix := commonPrefix(paths); len(prefix) > 0 {
+		return vyos.FormatPath(prefix)
+	}
+
+	sorted := append([]string{}, commands...)
+	sort.Strings(sorted)
+	sum := sha256.Sum256([]byte(strings.Join(sorted, "\n")))
+	return "commands-" + hex.EncodeToString(sum[:8])
+}
+
+// commonPrefix returns the path components every path starts with, leaving
+// out the value of a single command.
+func commonPrefix(paths [][]string) []string {
+	if len(paths) == 0 {
+		return []string{}
+	}
+
+	prefix := paths[0][:len(paths[0])-1]
+	for _, components := range paths[1:] {
+		length := 0
+		for length < len(prefix) && length < len(components)-1 && prefix[length] == components[length] {
+			length++
+		}
+		prefix = prefix[:length]
+	}
+	return prefix
+}
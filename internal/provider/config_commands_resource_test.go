@@ -0,0 +1,86 @@
+package provider
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccConfigCommandsResource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			if server := testAccPreCheck(t); server != nil {
+				server.SetMulti("address")
+			}
+		},
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: testAccConfigCommandsResourceConfig(
+					"set interfaces dummy dum0 address 10.0.0.1/24",
+					"set interfaces dummy dum0 address 10.0.1.1/24",
+					"set interfaces dummy dum0 description 'Managed by Terraform'",
+				),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("vyos_config_commands.test", "id", "interfaces dummy dum0"),
+					resource.TestCheckResourceAttr("vyos_config_commands.test", "commands.#", "3"),
+					resource.TestCheckResourceAttr("data.vyos_config.test", "value", `{"address":["10.0.0.1/24","10.0.1.1/24"],"description":"Managed by Terraform"}`),
+				),
+			},
+			// Update testing, the removed address is deleted
+			{
+				Config: testAccConfigCommandsResourceConfig(
+					"set interfaces dummy dum0 address 10.0.0.1/24",
+					"set interfaces dummy dum0 description 'Managed by Terraform'",
+					"set interfaces dummy dum0 disable",
+				),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("vyos_config_commands.test", "commands.#", "3"),
+					resource.TestCheckResourceAttr("data.vyos_config.test", "value", `{"address":"10.0.0.1/24","description":"Managed by Terraform","disable":{}}`),
+				),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func testAccConfigCommandsResourceConfig(commands ...string) string {
+	quoted := make([]string, len(commands))
+	for i, command := range commands {
+		quoted[i] = fmt.Sprintf("%q", command)
+	}
+
+	return fmt.Sprintf(`
+resource "vyos_config_commands" "test" {
+  commands = [
+    %[1]s,
+  ]
+}
+
+data "vyos_config" "test" {
+  path       = "interfaces dummy dum0"
+  depends_on = [vyos_config_commands.test]
+}
+`, strings.Join(quoted, ",\n    "))
+}
+
+func TestCommandsId(t *testing.T) {
+	commands := []string{"set system host-name router", "set firewall all-ping enable"}
+	paths := [][]string{{"system", "host-name", "router"}, {"firewall", "all-ping", "enable"}}
+
+	id := commandsId(commands, paths)
+	if !strings.HasPrefix(id, "commands-") {
+		t.Errorf("expected a hash for commands sharing no path, got %q", id)
+	}
+	reversed := commandsId([]string{commands[1], commands[0]}, [][]string{paths[1], paths[0]})
+	if reversed != id {
+		t.Errorf("expected the id not to depend on the order of the commands, got %q and %q", id, reversed)
+	}
+
+	if id := commandsId(commands[:1], paths[:1]); id != "system host-name" {
+		t.Errorf("unexpected id: %q", id)
+	}
+}
@@ -0,0 +1,150 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/TGNThump/terraform-provider-vyos/internal/vyos"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces
+var _ datasource.DataSource = &ConfigDataSource{}
+var _ datasource.DataSourceWithConfigure = &ConfigDataSource{}
+
+func NewConfigDataSource() datasource.DataSource {
+	return &ConfigDataSource{}
+}
+
+// ConfigDataSource defines the data source implementation.
+type ConfigDataSource struct {
+	vyosConfig *vyos.VyosConfig
+}
+
+// ConfigDataSourceModel describes the data source data model.
+type ConfigDataSourceModel struct {
+	Path   types.String `tfsdk:"path"`
+	Value  types.String `tfsdk:"value"`
+	Exists types.Bool   `tfsdk:"exists"`
+	Keys   types.List   `tfsdk:"keys"`
+	Id     types.String `tfsdk:"id"`
+}
+
+func (d *ConfigDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_config"
+}
+
+func (d *ConfigDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Reads a subtree of the router configuration without managing it.",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Configuration identifier",
+			},
+			"path": schema.StringAttribute{
+				MarkdownDescription: "Configuration path, with components separated by spaces. Components containing spaces are " +
+					"quoted as on the VyOS CLI. An empty path reads the whole configuration.",
+				Required: true,
+				Validators: []validator.String{
+					pathValidator{allowEmpty: true},
+				},
+			},
+			"value": schema.StringAttribute{
+				MarkdownDescription: "JSON configuration under the path, null if the path does not exist.",
+				Computed:            true,
+			},
+			"exists": schema.BoolAttribute{
+				MarkdownDescription: "Whether the path exists in the configuration.",
+				Computed:            true,
+			},
+			"keys": schema.ListAttribute{
+				MarkdownDescription: "Sorted names of the child nodes under the path, such as the names of a tag node. " +
+					"Empty for leaf values.",
+				ElementType: types.StringType,
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func (d *ConfigDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	vyosConfig, ok := req.ProviderData.(*vyos.VyosConfig)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *vyos.VyosConfig, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.vyosConfig = vyosConfig
+}
+
+func (d *ConfigDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data ConfigDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Info(ctx, "Reading path "+data.Path.ValueString())
+
+	components, err := vyos.ParsePath(data.Path.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("path"), "Invalid configuration path", err.Error())
+		return
+	}
+
+	config, err := d.vyosConfig.Show(ctx, components)
+	if err != nil {
+		addVyosError(&resp.Diagnostics, path.Root("path"), err)
+		return
+	}
+
+	keys := []string{}
+	if tree, ok := config.(map[string]any); ok {
+		for key := range tree {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+	}
+
+	data.Id = data.Path
+	data.Exists = types.BoolValue(config != nil)
+	data.Value = types.StringNull()
+
+	if config != nil {
+		jsonValue, err := json.Marshal(config)
+		if err != nil {
+			resp.Diagnostics.AddError("Unable to encode configuration", "The configuration read from the router could not be encoded as JSON: "+err.Error())
+			return
+		}
+		data.Value = types.StringValue(string(jsonValue))
+	}
+
+	keysValue, diags := types.ListValueFrom(ctx, types.StringType, keys)
+	resp.Diagnostics.Append(diags...)
+	data.Keys = keysValue
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
@@ -0,0 +1,92 @@
+package provider
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccConfigDataSource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			if server := testAccPreCheck(t); server != nil {
+				server.SetVersion("1.3.8")
+			}
+		},
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Read testing
+			{
+				Config: testAccConfigDataSourceConfig("firewall name"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.vyos_config.test", "exists", "true"),
+					resource.TestCheckResourceAttr("data.vyos_config.test", "value", `{"default-action":"drop","rule":{"10":{"action":"accept"},"20":{"action":"drop"}}}`),
+					resource.TestCheckResourceAttr("data.vyos_config.rules", "keys.#", "2"),
+					resource.TestCheckResourceAttr("data.vyos_config.rules", "keys.0", "10"),
+					resource.TestCheckResourceAttr("data.vyos_config.rules", "keys.1", "20"),
+					resource.TestCheckResourceAttr("data.vyos_config.missing", "exists", "false"),
+					resource.TestCheckNoResourceAttr("data.vyos_config.missing", "value"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccConfigDataSourceVyos14(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			if server := testAccPreCheck(t); server != nil {
+				server.SetVersion("1.4.0")
+			}
+		},
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Read testing
+			{
+				Config: testAccConfigDataSourceConfig("firewall ipv4 name"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.vyos_config.test", "exists", "true"),
+					resource.TestCheckResourceAttr("data.vyos_config.test", "value", `{"default-action":"drop","rule":{"10":{"action":"accept"},"20":{"action":"drop"}}}`),
+					resource.TestCheckResourceAttr("data.vyos_config.rules", "keys.#", "2"),
+					resource.TestCheckResourceAttr("data.vyos_config.rules", "keys.0", "10"),
+					resource.TestCheckResourceAttr("data.vyos_config.rules", "keys.1", "20"),
+					resource.TestCheckResourceAttr("data.vyos_config.missing", "exists", "false"),
+					resource.TestCheckNoResourceAttr("data.vyos_config.missing", "value"),
+				),
+			},
+		},
+	})
+}
+
+// testAccConfigDataSourceConfig reads a firewall ruleset under rulesets, the
+// path of named rulesets of the VyOS version.
+func testAccConfigDataSourceConfig(rulesets string) string {
+	return fmt.Sprintf(`
+resource "vyos_config" "test" {
+  path  = "%[1]s TEST"
+  value = jsonencode({
+    default-action = "drop"
+    rule = {
+      10 = { action = "accept" }
+      20 = { action = "drop" }
+    }
+  })
+}
+
+data "vyos_config" "test" {
+  path       = "%[1]s TEST"
+  depends_on = [vyos_config.test]
+}
+
+data "vyos_config" "rules" {
+  path       = "%[1]s TEST rule"
+  depends_on = [vyos_config.test]
+}
+
+data "vyos_config" "missing" {
+  path       = "%[1]s MISSING"
+  depends_on = [vyos_config.test]
+}
+`, rulesets)
+}
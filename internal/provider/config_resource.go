@@ -4,15 +4,19 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"strings"
+	"time"
 
 	"github.com/TGNThump/terraform-provider-vyos/internal/vyos"
-	"github.com/foltik/vyos-client-go/client"
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
@@ -21,6 +25,7 @@ import (
 var _ resource.Resource = &ConfigResource{}
 var _ resource.ResourceWithImportState = &ConfigResource{}
 var _ resource.ResourceWithConfigure = &ConfigResource{}
+var _ resource.ResourceWithValidateConfig = &ConfigResource{}
 
 func NewConfigResource() resource.Resource {
 	return &ConfigResource{}
@@ -34,8 +39,29 @@ type ConfigResource struct {
 // ConfigResourceModel describes the resource data model.
 type ConfigResourceModel struct {
 	Path  types.String `tfsdk:"path"`
-	Value types.String `tfsdk:"value"`
+	Value ConfigValue  `tfsdk:"value"`
+	Mode  types.String `tfsdk:"mode"`
 	Id    types.String `tfsdk:"id"`
+
+	Timeouts timeouts.Value `tfsdk:"timeouts"`
+}
+
+const (
+	// ModeAuthoritative manages the whole subtree under path.
+	ModeAuthoritative = "authoritative"
+	// ModeMerge manages only the nodes declared in value.
+	ModeMerge = "merge"
+)
+
+// defaultConfigTimeout is how long each operation on a configuration
+// resource may take when its timeouts block does not say otherwise.
+const defaultConfigTimeout = 20 * time.Minute
+
+// privateState is the subset of resource private state used to remember the
+// values VyOS fills in on its own.
+type privateState interface {
+	GetKey(ctx context.Context, key string) ([]byte, diag.Diagnostics)
+	SetKey(ctx context.Context, key string, value []byte) diag.Diagnostics
 }
 
 func (r *ConfigResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -55,17 +81,45 @@ func (r *ConfigResource) Schema(ctx context.Context, request resource.SchemaRequ
 				},
 			},
 			"path": schema.StringAttribute{
-				MarkdownDescription: "Configuration path",
-				Required:            true,
+				MarkdownDescription: "Configuration path, with components separated by spaces. Components containing spaces are " +
+					"quoted as on the VyOS CLI, for example `system login user vyos full-name 'VyOS User'`. Paths and values are " +
+					"checked against the configuration schema of the router's VyOS version when planning; subtrees the " +
+					"embedded schema does not describe are not checked.",
+				Required: true,
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.RequiresReplace(),
 				},
+				Validators: []validator.String{
+					pathValidator{},
+				},
 			},
 			"value": schema.StringAttribute{
-				MarkdownDescription: "JSON configuration for the path",
-				Optional:            true,
+				MarkdownDescription: "JSON configuration for the path. Values are compared semantically, so key order, numbers or " +
+					"booleans written as strings, and leaf lists holding a single value do not cause a diff.",
+				Optional:   true,
+				CustomType: ConfigValueType{},
+			},
+			"mode": schema.StringAttribute{
+				MarkdownDescription: "How the subtree under `path` is managed. `authoritative` replaces the whole subtree and reports " +
+					"any node added outside of Terraform as a change. `merge` only manages the nodes declared in `value`, " +
+					"so a subtree can be shared with other resources or teams. Defaults to `authoritative`.",
+				Optional: true,
+				Computed: true,
+				Default:  stringdefault.StaticString(ModeAuthoritative),
+				Validators: []validator.String{
+					stringvalidator.OneOf(ModeAuthoritative, ModeMerge),
+				},
 			},
 		},
+
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Create: true,
+				Read:   true,
+				Update: true,
+				Delete: true,
+			}),
+		},
 	}
 }
 
@@ -89,6 +143,56 @@ func (r *ConfigResource) Configure(ctx context.Context, req resource.ConfigureRe
 	r.vyosConfig = vyosConfig
 }
 
+// ValidateConfig checks path and value against the configuration schema of
+// the router version, or of every supported version while it is not known,
+// so typos are caught when planning rather than when committing.
+func (r *ConfigResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var data ConfigResourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if data.Path.IsNull() || data.Path.IsUnknown() || data.Value.IsUnknown() {
+		return
+	}
+
+	// Paths and values which cannot be parsed are reported by the attribute
+	// validators.
+	components, err := vyos.ParsePath(data.Path.ValueString())
+	if err != nil || len(components) == 0 {
+		return
+	}
+	var value any
+	if !data.Value.IsNull() {
+		if value, err = data.Value.Unmarshal(); err != nil {
+			return
+		}
+	}
+
+	version := vyos.Version{}
+	if r.vyosConfig != nil {
+		version = r.vyosConfig.Version(ctx)
+	}
+
+	schemaErrs, err := vyos.ValidateSchema(version, components, value)
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to load the VyOS configuration schema", err.Error())
+		return
+	}
+
+	for _, schemaErr := range schemaErrs {
+		// Errors within the path are reported on path, errors in nodes of the
+		// value on value.
+		attribute, summary := path.Root("value"), "Invalid configuration value"
+		if len(schemaErr.Path) <= len(components) {
+			attribute, summary = path.Root("path"), "Invalid configuration path"
+		}
+		resp.Diagnostics.AddAttributeError(attribute, summary, schemaErr.Error())
+	}
+}
+
 func (r *ConfigResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
 	var data *ConfigResourceModel
 
@@ -99,21 +203,34 @@ func (r *ConfigResource) Create(ctx context.Context, req resource.CreateRequest,
 		return
 	}
 
+	createTimeout, diags := data.Timeouts.Create(ctx, defaultConfigTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, createTimeout)
+	defer cancel()
+
 	// Check if config already exists
 	tflog.Info(ctx, "Reading path "+data.Path.ValueString())
 
-	components := strings.Split(data.Path.ValueString(), " ")
-	parentPath := strings.Join(components[0:len(components)-1], " ")
+	components, diags := pathComponents(data.Path)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	parentPath := components[0 : len(components)-1]
 	terminal := components[len(components)-1]
 
 	parent, err := r.vyosConfig.Show(ctx, parentPath)
 	if err != nil {
-		resp.Diagnostics.AddError("No", err.Error())
+		addVyosError(&resp.Diagnostics, path.Root("path"), err)
 		return
 	}
 
-	if parent != nil {
-		existing := parent.(map[string]any)[terminal]
+	if parent != nil && data.Mode.ValueString() != ModeMerge {
+		existing := vyos.Child(parent, terminal)
 
 		if existing != nil {
 			resp.Diagnostics.AddError(fmt.Sprintf("Configuration path '%s' already exists, try a resource import instead.", data.Path.ValueString()), fmt.Sprintf("%v", existing))
@@ -121,24 +238,32 @@ func (r *ConfigResource) Create(ctx context.Context, req resource.CreateRequest,
 		}
 	}
 
-	var jsonValue interface{}
-	err = json.Unmarshal([]byte(data.Value.ValueString()), &jsonValue)
-
+	jsonValue, err := unmarshalConfigValue(data.Value)
 	if err != nil {
-		resp.Diagnostics.AddError("No", err.Error())
+		resp.Diagnostics.AddAttributeError(path.Root("value"), "Invalid configuration value", "The value must be a JSON document: "+err.Error())
 		return
 	}
 
 	tflog.Info(ctx, "Setting path "+data.Path.ValueString()+" to value "+data.Value.ValueString())
 
-	err = r.vyosConfig.Set(ctx, data.Path.ValueString(), jsonValue)
+	if data.Mode.ValueString() == ModeMerge {
+		var current any
+		if parent != nil {
+			current = vyos.Child(parent, terminal)
+		}
+		err = r.vyosConfig.Configure(ctx, vyos.Diff(components, nil, jsonValue, current))
+	} else {
+		err = r.vyosConfig.Set(ctx, components, jsonValue)
+	}
 	if err != nil {
-		resp.Diagnostics.AddError("No", err.Error())
+		addVyosError(&resp.Diagnostics, path.Root("value"), err)
 		return
 	}
 
 	data.Id = types.StringValue(data.Path.ValueString())
 
+	resp.Diagnostics.Append(r.recordDefaults(ctx, components, jsonValue, resp.Private)...)
+
 	tflog.Info(ctx, "Set path "+data.Path.ValueString()+" to value "+data.Value.ValueString())
 
 	// Save data into Terraform state
@@ -155,15 +280,28 @@ func (r *ConfigResource) Read(ctx context.Context, req resource.ReadRequest, res
 		return
 	}
 
+	readTimeout, diags := data.Timeouts.Read(ctx, defaultConfigTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, readTimeout)
+	defer cancel()
+
 	tflog.Info(ctx, "Reading path "+data.Path.ValueString())
 
-	components := strings.Split(data.Path.ValueString(), " ")
-	parentPath := strings.Join(components[0:len(components)-1], " ")
+	components, diags := pathComponents(data.Path)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	parentPath := components[0 : len(components)-1]
 	terminal := components[len(components)-1]
 
 	parent, err := r.vyosConfig.Show(ctx, parentPath)
 	if err != nil {
-		resp.Diagnostics.AddError("No", err.Error())
+		addVyosError(&resp.Diagnostics, path.Root("path"), err)
 		return
 	}
 
@@ -172,20 +310,54 @@ func (r *ConfigResource) Read(ctx context.Context, req resource.ReadRequest, res
 		return
 	}
 
-	config := parent.(map[string]any)[terminal]
+	config := vyos.Child(parent, terminal)
 
 	if config == nil {
 		resp.Diagnostics.AddError("Resource not found", "Parent not found")
 		return
 	}
 
-	jsonValue, err := json.Marshal(config)
-	if err != nil {
-		resp.Diagnostics.AddError("No", err.Error())
-		return
+	if data.Mode.IsNull() {
+		data.Mode = types.StringValue(ModeAuthoritative)
 	}
 
-	data.Value = types.StringValue(string(jsonValue[:]))
+	// Nodes outside of value are managed elsewhere in merge mode.
+	if data.Mode.ValueString() == ModeMerge {
+		declared, _ := data.Value.Unmarshal()
+		if data.Value.IsNull() {
+			declared = map[string]any{}
+		}
+		config = vyos.Declared(config, declared)
+	}
+
+	defaultsJson, diags := req.Private.GetKey(ctx, "defaults")
+	resp.Diagnostics.Append(diags...)
+
+	// A valueless node is returned as an empty object, which is what a null
+	// value is set as. Imported resources have no recorded defaults and always
+	// report the object.
+	if tree, ok := config.(map[string]any); ok && len(tree) == 0 && data.Value.IsNull() && len(defaultsJson) > 0 {
+		config = nil
+	}
+
+	if config != nil {
+		declared, _ := data.Value.Unmarshal()
+
+		if len(defaultsJson) > 0 {
+			var defaults any
+			if err := json.Unmarshal(defaultsJson, &defaults); err == nil {
+				config = vyos.WithoutDefaults(config, defaults, declared)
+			}
+		}
+
+		jsonValue, err := json.Marshal(config)
+		if err != nil {
+			resp.Diagnostics.AddError("Unable to encode configuration", "The configuration read from the router could not be encoded as JSON: "+err.Error())
+			return
+		}
+
+		data.Value = NewConfigValue(string(jsonValue[:]))
+	}
 
 	tflog.Info(ctx, "Read path "+data.Path.ValueString()+" with value "+data.Value.ValueString())
 
@@ -204,55 +376,76 @@ func (r *ConfigResource) Update(ctx context.Context, req resource.UpdateRequest,
 		return
 	}
 
+	updateTimeout, diags := plan.Timeouts.Update(ctx, defaultConfigTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, updateTimeout)
+	defer cancel()
+
 	tflog.Info(ctx, "Updating path "+plan.Path.ValueString()+" to value "+plan.Value.ValueString())
 
-	var payload []map[string]any
+	components, diags := pathComponents(plan.Path)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
-	payload = append(payload, map[string]any{
-		"op":   "delete",
-		"path": strings.Split(plan.Path.ValueString(), " "),
-	})
+	value, err := unmarshalConfigValue(plan.Value)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("value"), "Invalid configuration value", "The value must be a JSON document: "+err.Error())
+		return
+	}
 
-	{
-		var value interface{}
-		err := json.Unmarshal([]byte(plan.Value.ValueString()), &value)
+	current, err := r.vyosConfig.Show(ctx, components)
+	if err != nil {
+		addVyosError(&resp.Diagnostics, path.Root("path"), err)
+		return
+	}
 
+	var old any
+	if plan.Mode.ValueString() == ModeMerge {
+		old, err = unmarshalConfigValue(state.Value)
 		if err != nil {
-			resp.Diagnostics.AddError("No", err.Error())
+			resp.Diagnostics.AddError("Invalid prior configuration value", "The value in the Terraform state is not a JSON document: "+err.Error())
 			return
 		}
 
-		flat, err := client.Flatten(value)
-		if err != nil {
-			resp.Diagnostics.AddError("No", err.Error())
-			return
+		// Switching to merge mode leaves nodes outside of value alone.
+		if state.Mode.ValueString() != ModeMerge {
+			old = vyos.Declared(old, value)
 		}
-
-		for _, pair := range flat {
-			subpath, value := pair[0], pair[1]
-
-			prefixpath := plan.Path.ValueString()
-			if len(prefixpath) > 0 && len(subpath) > 0 {
-				prefixpath += " "
-			}
-			prefixpath += subpath
-
-			payload = append(payload, map[string]any{
-				"op":    "set",
-				"path":  strings.Split(prefixpath, " "),
-				"value": value,
-			})
+	} else {
+		// The router may have drifted from the state, so the value is
+		// compared with what it holds now, leaving out the nodes VyOS filled
+		// in.
+		old = current
+		defaultsJson, diags := req.Private.GetKey(ctx, "defaults")
+		resp.Diagnostics.Append(diags...)
+		var defaults any
+		if len(defaultsJson) > 0 && json.Unmarshal(defaultsJson, &defaults) == nil {
+			old = vyos.WithoutDefaults(current, defaults, value)
 		}
 	}
 
-	tflog.Info(ctx, fmt.Sprintf("%v", payload))
+	// Only the leaves which changed are sent, in a single commit, so
+	// unchanged parts of the subtree are not disrupted.
+	payload := vyos.Diff(components, old, value, current)
 
-	_, err := r.vyosConfig.ApiRequest(ctx, "configure", payload)
+	tflog.Info(ctx, fmt.Sprintf("Computed %d operations for path %s", len(payload), plan.Path.ValueString()), map[string]interface{}{
+		"operations": payload,
+	})
+
+	err = r.vyosConfig.Configure(ctx, payload)
 	if err != nil {
-		resp.Diagnostics.AddError("No", err.Error())
+		addVyosError(&resp.Diagnostics, path.Root("value"), err)
 		return
 	}
 
+	resp.Diagnostics.Append(r.recordDefaults(ctx, components, value, resp.Private)...)
+
 	tflog.Info(ctx, "Updated path "+plan.Path.ValueString()+" to value "+plan.Value.ValueString())
 
 	// Save updated plan into Terraform state
@@ -269,11 +462,38 @@ func (r *ConfigResource) Delete(ctx context.Context, req resource.DeleteRequest,
 		return
 	}
 
+	deleteTimeout, diags := data.Timeouts.Delete(ctx, defaultConfigTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, deleteTimeout)
+	defer cancel()
+
 	tflog.Info(ctx, "Deleting path "+data.Path.ValueString())
 
-	err := r.vyosConfig.Delete(ctx, data.Path.ValueString())
+	components, diags := pathComponents(data.Path)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var err error
+	if data.Mode.ValueString() == ModeMerge {
+		var declared, current any
+		declared, err = unmarshalConfigValue(data.Value)
+		if err == nil {
+			current, err = r.vyosConfig.Show(ctx, components)
+		}
+		if err == nil {
+			err = r.vyosConfig.Configure(ctx, vyos.Diff(components, declared, nil, current))
+		}
+	} else {
+		err = r.vyosConfig.Delete(ctx, components)
+	}
 	if err != nil {
-		resp.Diagnostics.AddError("No", err.Error())
+		addVyosError(&resp.Diagnostics, path.Root("path"), err)
 		return
 	}
 
@@ -284,3 +504,52 @@ func (r *ConfigResource) ImportState(ctx context.Context, req resource.ImportSta
 	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), req.ID)...)
 	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("path"), req.ID)...)
 }
+
+// unmarshalConfigValue decodes a value into the normalized configuration tree
+// to be set, where a null value sets a valueless node.
+func unmarshalConfigValue(value ConfigValue) (any, error) {
+	if value.IsNull() {
+		return map[string]any{}, nil
+	}
+
+	jsonValue, err := value.Unmarshal()
+	if err != nil {
+		return nil, err
+	}
+	return vyos.Normalize(jsonValue), nil
+}
+
+// recordDefaults remembers the nodes VyOS filled in under components that were not
+// part of the declared value, so Read does not report them as a change.
+func (r *ConfigResource) recordDefaults(ctx context.Context, components []string, declared any, private privateState) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	config, err := r.vyosConfig.Show(ctx, components)
+	if err != nil {
+		addVyosError(&diags, path.Root("value"), err)
+		return diags
+	}
+
+	defaultsJson, err := json.Marshal(vyos.Undeclared(config, declared))
+	if err != nil {
+		diags.AddError("Unable to encode configuration", "The values filled in by VyOS could not be encoded as JSON: "+err.Error())
+		return diags
+	}
+
+	tflog.Debug(ctx, "Recording values filled in by VyOS: "+string(defaultsJson))
+
+	return private.SetKey(ctx, "defaults", defaultsJson)
+}
+
+// pathComponents splits a path attribute into its components.
+func pathComponents(value types.String) ([]string, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	components, err := vyos.ParsePath(value.ValueString())
+	if err != nil {
+		diags.AddAttributeError(path.Root("path"), "Invalid configuration path", err.Error())
+	} else if len(components) == 0 {
+		diags.AddAttributeError(path.Root("path"), "Invalid configuration path", "The configuration path cannot be empty.")
+	}
+	return components, diags
+}
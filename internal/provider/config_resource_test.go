@@ -2,14 +2,20 @@ package provider
 
 import (
 	"fmt"
+	"regexp"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 )
 
 func TestAccConfigResourceSimpleFirewallRuleset(t *testing.T) {
 	resource.Test(t, resource.TestCase{
-		PreCheck:                 func() { testAccPreCheck(t) },
+		PreCheck: func() {
+			if server := testAccPreCheck(t); server != nil {
+				server.SetVersion("1.3.8")
+			}
+		},
 		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
 		Steps: []resource.TestStep{
 			// Create and Read testing
@@ -42,7 +48,11 @@ func TestAccConfigResourceSimpleFirewallRuleset(t *testing.T) {
 
 func TestAccConfigResourceComplexFirewallRuleset(t *testing.T) {
 	resource.Test(t, resource.TestCase{
-		PreCheck:                 func() { testAccPreCheck(t) },
+		PreCheck: func() {
+			if server := testAccPreCheck(t); server != nil {
+				server.SetVersion("1.3.8")
+			}
+		},
 		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
 		Steps: []resource.TestStep{
 			// Create and Read testing
@@ -107,6 +117,256 @@ func TestAccConfigResourceBinaryOption(t *testing.T) {
 	})
 }
 
+func TestAccConfigResourceSemanticValue(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing, a non-empty plan after apply fails the step
+			{
+				Config: testAccConfigResourceConfig("interfaces dummy dum0",
+					`jsonencode({
+								mtu     = 1500
+								address = ["10.0.0.1/24"]
+							})`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("vyos_config.test", "value", `{"address":["10.0.0.1/24"],"mtu":1500}`),
+				),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func TestAccConfigResourceQuotedPath(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			if server := testAccPreCheck(t); server != nil {
+				server.SetLeaves("full-name", "pre-login")
+			}
+		},
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: testAccConfigResourceConfig("system login user vyos full-name", `jsonencode("VyOS User")`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("vyos_config.test", "value", `"VyOS User"`),
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:            "vyos_config.test",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{},
+			},
+			// Quoted path components
+			{
+				Config: testAccConfigResourceConfig("system login banner pre-login 'Authorised access only'", `null`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("vyos_config.test", "id", "system login banner pre-login 'Authorised access only'"),
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:            "vyos_config.test",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"value"},
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func TestAccConfigResourceLeafValuePath(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			server := testAccPreCheck(t)
+			if server == nil {
+				t.Skip("the existing banner is only set up on the fake router")
+			}
+			// VyOS holds the value as the leaf pre-login, not as a node under it.
+			server.SetConfig(map[string]any{
+				"system": map[string]any{"login": map[string]any{"banner": map[string]any{"pre-login": "Authorised access only"}}},
+			})
+		},
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create finds the existing value
+			{
+				Config:      testAccConfigResourceConfig("system login banner pre-login 'Authorised access only'", `null`),
+				ExpectError: regexp.MustCompile(`already\s+exists`),
+			},
+			// ImportState testing
+			{
+				Config:        testAccConfigResourceConfig("system login banner pre-login 'Authorised access only'", `null`),
+				ResourceName:  "vyos_config.test",
+				ImportState:   true,
+				ImportStateId: "system login banner pre-login 'Authorised access only'",
+				ImportStateCheck: func(states []*terraform.InstanceState) error {
+					if len(states) != 1 || states[0].Attributes["value"] != "{}" {
+						return fmt.Errorf("unexpected imported state: %v", states)
+					}
+					return nil
+				},
+			},
+			// A leaf holding another value is not found
+			{
+				Config:        testAccConfigResourceConfig("system login banner pre-login 'Authorised access only'", `null`),
+				ResourceName:  "vyos_config.test",
+				ImportState:   true,
+				ImportStateId: "system login banner pre-login 'Welcome'",
+				ExpectError:   regexp.MustCompile(`Resource not found`),
+			},
+		},
+	})
+}
+
+func TestAccConfigResourceMergeMode(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			if server := testAccPreCheck(t); server != nil {
+				server.SetVersion("1.3.8")
+			}
+		},
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing, each resource only reports its own rule
+			{
+				Config: testAccConfigResourceMergeConfig(true),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("vyos_config.test", "mode", "merge"),
+					resource.TestCheckResourceAttr("vyos_config.test", "value", `{"rule":{"10":{"action":"accept"}}}`),
+					resource.TestCheckResourceAttr("vyos_config.other", "value", `{"default-action":"drop","rule":{"20":{"action":"drop"}}}`),
+				),
+			},
+			// Update testing
+			{
+				Config: testAccConfigResourceMergeConfig(false),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("vyos_config.test", "value", `{"rule":{"10":{"action":"drop"}}}`),
+					resource.TestCheckResourceAttr("vyos_config.other", "value", `{"default-action":"drop","rule":{"20":{"action":"drop"}}}`),
+				),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func TestAccConfigResourceTimeouts(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: `
+resource "vyos_config" "test" {
+  path  = "system static-host-mapping host-name timeouts.example.com"
+  value = jsonencode({ inet = "192.0.2.1" })
+
+  timeouts {
+    create = "5m"
+    read   = "1m"
+  }
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("vyos_config.test", "timeouts.create", "5m"),
+					resource.TestCheckResourceAttr("vyos_config.test", "timeouts.read", "1m"),
+				),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func TestAccConfigResourceSchemaValidation(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccConfigResourceConfig("firewall ipv4 nmae TEST", `jsonencode({default-action = "drop"})`),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`"nmae" is not a configuration node under firewall ipv4,\s+did you mean "name"\?`),
+			},
+			{
+				Config:      testAccConfigResourceConfig("firewall ipv4 name TEST", `jsonencode({rule = { 10 = { actoin = "drop" } }})`),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`firewall ipv4 name TEST rule 10 actoin:`),
+			},
+		},
+	})
+}
+
+func TestAccConfigResourceFirewallRulesetVyos14(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			if server := testAccPreCheck(t); server != nil {
+				server.SetVersion("1.4.0")
+			}
+		},
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: testAccConfigResourceConfig("firewall ipv4 name TEST",
+					`jsonencode({
+								default-action = "drop"
+								rule = {
+									10 = {
+										action = "accept"
+									}
+								}
+							})`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("vyos_config.test", "id", "firewall ipv4 name TEST"),
+					resource.TestCheckResourceAttr("vyos_config.test", "value", `{"default-action":"drop","rule":{"10":{"action":"accept"}}}`),
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:            "vyos_config.test",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{},
+			},
+			// Update and Read testing
+			{
+				Config: testAccConfigResourceConfig("firewall ipv4 name TEST", `jsonencode({"default-action" = "accept"})`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("vyos_config.test", "value", `{"default-action":"accept"}`),
+				),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func testAccConfigResourceMergeConfig(accept bool) string {
+	action := "drop"
+	if accept {
+		action = "accept"
+	}
+
+	return fmt.Sprintf(`
+resource "vyos_config" "test" {
+  path  = "firewall name TEST"
+  mode  = "merge"
+  value = jsonencode({ rule = { 10 = { action = %[1]q } } })
+}
+
+resource "vyos_config" "other" {
+  path  = "firewall name TEST"
+  mode  = "merge"
+  value = jsonencode({ default-action = "drop", rule = { 20 = { action = "drop" } } })
+}
+`, action)
+}
+
 func testAccConfigResourceConfig(path string, value string) string {
 	return fmt.Sprintf(`
 resource "vyos_config" "test" {
@@ -0,0 +1,116 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/TGNThump/terraform-provider-vyos/internal/vyos"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// Ensure the custom types fully satisfy framework interfaces
+var _ basetypes.StringTypable = ConfigValueType{}
+var _ basetypes.StringValuableWithSemanticEquals = ConfigValue{}
+
+// ConfigValueType is a string attribute type holding a JSON encoded VyOS
+// configuration tree.
+type ConfigValueType struct {
+	basetypes.StringType
+}
+
+func (t ConfigValueType) Equal(o attr.Type) bool {
+	other, ok := o.(ConfigValueType)
+	if !ok {
+		return false
+	}
+	return t.StringType.Equal(other.StringType)
+}
+
+func (t ConfigValueType) String() string {
+	return "ConfigValueType"
+}
+
+func (t ConfigValueType) ValueFromString(ctx context.Context, in basetypes.StringValue) (basetypes.StringValuable, diag.Diagnostics) {
+	return ConfigValue{StringValue: in}, nil
+}
+
+func (t ConfigValueType) ValueFromTerraform(ctx context.Context, in tftypes.Value) (attr.Value, error) {
+	attrValue, err := t.StringType.ValueFromTerraform(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+
+	stringValue, ok := attrValue.(basetypes.StringValue)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value type of %T", attrValue)
+	}
+
+	return ConfigValue{StringValue: stringValue}, nil
+}
+
+func (t ConfigValueType) ValueType(ctx context.Context) attr.Value {
+	return ConfigValue{}
+}
+
+// ConfigValue is a JSON encoded VyOS configuration tree. Two values are
+// semantically equal when they describe the same configuration, regardless of
+// key order, numbers written as strings, or leaf lists with a single value.
+type ConfigValue struct {
+	basetypes.StringValue
+}
+
+func NewConfigValue(value string) ConfigValue {
+	return ConfigValue{StringValue: basetypes.NewStringValue(value)}
+}
+
+func NewConfigValueNull() ConfigValue {
+	return ConfigValue{StringValue: basetypes.NewStringNull()}
+}
+
+func (v ConfigValue) Equal(o attr.Value) bool {
+	other, ok := o.(ConfigValue)
+	if !ok {
+		return false
+	}
+	return v.StringValue.Equal(other.StringValue)
+}
+
+func (v ConfigValue) Type(ctx context.Context) attr.Type {
+	return ConfigValueType{}
+}
+
+func (v ConfigValue) StringSemanticEquals(ctx context.Context, newValuable basetypes.StringValuable) (bool, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	newValue, ok := newValuable.(ConfigValue)
+	if !ok {
+		diags.AddError(
+			"Semantic Equality Check Error",
+			fmt.Sprintf("Expected value type %T, got: %T. Please report this issue to the provider developers.", v, newValuable),
+		)
+		return false, diags
+	}
+
+	prior, err := v.Unmarshal()
+	if err != nil {
+		return false, nil
+	}
+
+	proposed, err := newValue.Unmarshal()
+	if err != nil {
+		return false, nil
+	}
+
+	return vyos.Equal(prior, proposed), nil
+}
+
+// Unmarshal decodes the JSON configuration tree.
+func (v ConfigValue) Unmarshal() (any, error) {
+	var value any
+	err := json.Unmarshal([]byte(v.ValueString()), &value)
+	return value, err
+}
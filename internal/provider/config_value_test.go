@@ -0,0 +1,29 @@
+package provider
+
+import (
+	"context"
+	"testing"
+)
+
+func TestConfigValueSemanticEquals(t *testing.T) {
+	cases := []struct {
+		prior, proposed string
+		expected        bool
+	}{
+		{`{"default-action":"drop"}`, `{"default-action":"drop"}`, true},
+		{`{"rule":{"10":{"action":"accept"}},"default-action":"drop"}`, `{"default-action":"drop","rule":{"10":{"action":"accept"}}}`, true},
+		{`{"address":["10.0.0.1/24"],"mtu":1500}`, `{"address":"10.0.0.1/24","mtu":"1500"}`, true},
+		{`{"default-action":"drop"}`, `{"default-action":"accept"}`, false},
+		{`not json`, `{}`, false},
+	}
+
+	for _, c := range cases {
+		equal, diags := NewConfigValue(c.prior).StringSemanticEquals(context.Background(), NewConfigValue(c.proposed))
+		if diags.HasError() {
+			t.Fatalf("unexpected diagnostics: %v", diags)
+		}
+		if equal != c.expected {
+			t.Errorf("%s == %s: got %v, expected %v", c.prior, c.proposed, equal, c.expected)
+		}
+	}
+}
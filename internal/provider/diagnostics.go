@@ -0,0 +1,126 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/TGNThump/terraform-provider-vyos/internal/vyos"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+)
+
+// addVyosError adds a diagnostic explaining err, an error returned while
+// talking to the router. Changes the router refused are attached to attribute,
+// the attribute holding the configuration that was sent.
+func addVyosError(diags *diag.Diagnostics, attribute path.Path, err error) {
+	if errors.Is(err, vyos.ErrCommitNotConfirmed) {
+		diags.AddError(
+			"VyOS change not confirmed",
+			"The change was committed with commit-confirm, but the VyOS API stopped answering afterwards, so it was "+
+				"not confirmed and the router will roll it back. Check that the change does not cut off access to the "+
+				"API, such as a firewall rule or an address change on the interface used to reach the router.\n\n"+
+				err.Error(),
+		)
+		return
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) {
+		diags.AddError(
+			"VyOS operation timed out",
+			"The router did not finish the operation in time. Raise the create, read, update or delete value of the "+
+				"timeouts block of the resource, or request_timeout of the provider for slow commits.\n\n"+err.Error(),
+		)
+		return
+	}
+
+	var vyosErr *vyos.Error
+	if !errors.As(err, &vyosErr) {
+		diags.AddError("VyOS request failed", err.Error())
+		return
+	}
+
+	if vyosErr.SSH {
+		addSSHError(diags, attribute, vyosErr, err)
+		return
+	}
+
+	switch vyosErr.Kind {
+	case vyos.ErrorAuth:
+		diags.AddError(
+			"VyOS API authentication failed",
+			"The router did not accept the API key. Check that api_key or VYOS_API_KEY matches a key under "+
+				"`service https api keys` on the router.\n\n"+err.Error(),
+		)
+	case vyos.ErrorConnectivity:
+		diags.AddError(
+			"Unable to reach the VyOS API",
+			"The router could not be reached or did not answer in time. Check that endpoint or VYOS_ENDPOINT is "+
+				"correct, that `service https api` is enabled on the router and that it can be reached from here.\n\n"+
+				err.Error(),
+		)
+	case vyos.ErrorTLS:
+		diags.AddError(
+			"Unable to verify the VyOS API certificate",
+			"The TLS connection to the router failed. For a self-signed or private CA certificate, set ca_cert_file "+
+				"or ca_cert_pem to the CA that signed it. When the certificate is issued for another name than the "+
+				"endpoint, set tls_server_name. Check that endpoint uses https:// for the HTTPS API. Only set insecure "+
+				"to skip verification on trusted networks.\n\n"+err.Error(),
+		)
+	case vyos.ErrorCommitValidation:
+		detail := "The router refused the change. Correct the configuration and apply again."
+		if len(vyosErr.Path) > 0 {
+			detail = fmt.Sprintf("The router refused the change to `%s`. Correct the configuration and apply again.",
+				vyos.FormatPath(vyosErr.Path))
+		}
+		diags.AddAttributeError(attribute, "VyOS rejected the configuration", detail+"\n\n"+err.Error())
+	case vyos.ErrorLockContention:
+		diags.AddError(
+			"VyOS configuration is locked",
+			"Another session is changing the configuration of the router, such as a user in configure mode or "+
+				"another commit. Wait for it to finish and apply again.\n\n"+err.Error(),
+		)
+	case vyos.ErrorServer:
+		diags.AddError(
+			"VyOS API server error",
+			fmt.Sprintf("The router answered with HTTP status %d. The API service may be restarting or have failed "+
+				"while handling the request. Check the router logs with `show log` and apply again.\n\n", vyosErr.StatusCode)+
+				err.Error(),
+		)
+	case vyos.ErrorMalformedResponse:
+		diags.AddError(
+			"Unexpected response from the VyOS API",
+			"The router answered with something other than a VyOS API response. Check that endpoint points at "+
+				"the VyOS HTTP API and not at a proxy or another service.\n\n"+err.Error(),
+		)
+	default:
+		diags.AddError("VyOS API request failed", err.Error())
+	}
+}
+
+// addSSHError adds a diagnostic explaining err, an error of the SSH transport.
+// Kinds which only the HTTP API returns are explained by addVyosError.
+func addSSHError(diags *diag.Diagnostics, attribute path.Path, vyosErr *vyos.Error, err error) {
+	switch vyosErr.Kind {
+	case vyos.ErrorAuth:
+		diags.AddError(
+			"VyOS SSH authentication failed",
+			"The router did not accept the SSH login, or its host key does not match. Check that ssh_user and "+
+				"ssh_password or ssh_private_key match a user under `system login` on the router, and that "+
+				"ssh_host_key is the current host key of the router.\n\n"+err.Error(),
+		)
+	case vyos.ErrorConnectivity:
+		diags.AddError(
+			"Unable to reach VyOS over SSH",
+			"The router could not be reached or did not answer in time. Check that ssh_host or VYOS_SSH_HOST is "+
+				"correct, that `service ssh` is enabled on the router and that it can be reached from here.\n\n"+
+				err.Error(),
+		)
+	case vyos.ErrorCommitValidation, vyos.ErrorLockContention:
+		unmarked := *vyosErr
+		unmarked.SSH = false
+		addVyosError(diags, attribute, &unmarked)
+	default:
+		diags.AddError("VyOS SSH command failed", err.Error())
+	}
+}
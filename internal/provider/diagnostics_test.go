@@ -0,0 +1,55 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/TGNThump/terraform-provider-vyos/internal/vyos"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+)
+
+func TestAddVyosError(t *testing.T) {
+	cases := []struct {
+		err       error
+		summary   string
+		attribute bool
+	}{
+		{&vyos.Error{Kind: vyos.ErrorAuth, Err: errors.New("Valid API key is required")}, "VyOS API authentication failed", false},
+		{&vyos.Error{Kind: vyos.ErrorConnectivity, Err: errors.New("connection refused")}, "Unable to reach the VyOS API", false},
+		{&vyos.Error{Kind: vyos.ErrorCommitValidation, Path: []string{"system", "host-name"}, Err: errors.New("Set failed")}, "VyOS rejected the configuration", true},
+		{&vyos.Error{Kind: vyos.ErrorLockContention, Err: errors.New("Configuration is locked")}, "VyOS configuration is locked", false},
+		{&vyos.Error{Kind: vyos.ErrorTLS, Err: errors.New("x509: certificate signed by unknown authority")}, "Unable to verify the VyOS API certificate", false},
+		{&vyos.Error{Kind: vyos.ErrorServer, StatusCode: 503, Err: errors.New("Service Unavailable")}, "VyOS API server error", false},
+		{&vyos.Error{Kind: vyos.ErrorMalformedResponse, Err: errors.New("invalid character '<'")}, "Unexpected response from the VyOS API", false},
+		{fmt.Errorf("%w, the router will roll back the change in 5 minutes", vyos.ErrCommitNotConfirmed), "VyOS change not confirmed", false},
+		{&vyos.Error{Kind: vyos.ErrorConnectivity, Err: context.DeadlineExceeded}, "VyOS operation timed out", false},
+		{&vyos.Error{Kind: vyos.ErrorAuth, SSH: true, Err: errors.New("ssh: unable to authenticate")}, "VyOS SSH authentication failed", false},
+		{&vyos.Error{Kind: vyos.ErrorConnectivity, SSH: true, Err: errors.New("connection refused")}, "Unable to reach VyOS over SSH", false},
+		{&vyos.Error{Kind: vyos.ErrorCommitValidation, SSH: true, Path: []string{"system", "host-name"}, Err: errors.New("Commit failed")}, "VyOS rejected the configuration", true},
+		{&vyos.Error{Kind: vyos.ErrorMalformedResponse, SSH: true, Err: errors.New("invalid character 'I'")}, "VyOS SSH command failed", false},
+		{errors.New("something else"), "VyOS request failed", false},
+	}
+
+	for _, c := range cases {
+		var diags diag.Diagnostics
+		addVyosError(&diags, path.Root("value"), c.err)
+
+		if len(diags) != 1 {
+			t.Fatalf("expected a single diagnostic for %v, got %v", c.err, diags)
+		}
+		if summary := diags[0].Summary(); summary != c.summary {
+			t.Errorf("unexpected summary for %v: %s, expected %s", c.err, summary, c.summary)
+		}
+		if !strings.Contains(diags[0].Detail(), c.err.Error()) {
+			t.Errorf("expected the detail to include the error, got %s", diags[0].Detail())
+		}
+		_, isAttribute := diags[0].(diag.DiagnosticWithPath)
+		if isAttribute != c.attribute {
+			t.Errorf("unexpected attribute diagnostic for %v: %v", c.err, isAttribute)
+		}
+	}
+}
@@ -0,0 +1,317 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/TGNThump/terraform-provider-vyos/internal/vyos"
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces
+var _ resource.Resource = &FirewallGroupMemberResource{}
+var _ resource.ResourceWithImportState = &FirewallGroupMemberResource{}
+var _ resource.ResourceWithConfigure = &FirewallGroupMemberResource{}
+var _ resource.ResourceWithValidateConfig = &FirewallGroupMemberResource{}
+
+func NewFirewallGroupMemberResource() resource.Resource {
+	return &FirewallGroupMemberResource{}
+}
+
+// FirewallGroupMemberResource manages a single member of a firewall group,
+// leaving its other members alone.
+type FirewallGroupMemberResource struct {
+	vyosConfig *vyos.VyosConfig
+}
+
+// FirewallGroupMemberResourceModel describes the resource data model.
+type FirewallGroupMemberResourceModel struct {
+	Id     types.String `tfsdk:"id"`
+	Group  types.String `tfsdk:"group"`
+	Type   types.String `tfsdk:"type"`
+	Member types.String `tfsdk:"member"`
+
+	Timeouts timeouts.Value `tfsdk:"timeouts"`
+}
+
+// firewallGroupMemberTypes are the kinds of group a member can be added to.
+var firewallGroupMemberTypes = []string{"address", "network"}
+
+func (r *FirewallGroupMemberResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_firewall_group_member"
+}
+
+func (r *FirewallGroupMemberResource) Schema(ctx context.Context, request resource.SchemaRequest, response *resource.SchemaResponse) {
+	response.Schema = schema.Schema{
+		MarkdownDescription: "A single member of a firewall address or network group. Only the member is created and deleted, so " +
+			"several configurations can add their own members to the same group. The group is created with the first member " +
+			"when it does not exist yet. Do not combine with a `vyos_firewall_group` managing the same group, which removes " +
+			"members it does not declare.",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Type, group and member separated by slashes, such as `network/ADMIN/192.0.2.0/24`",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"group": schema.StringAttribute{
+				MarkdownDescription: "Name of the group",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					firewallGroupNameValidator,
+				},
+			},
+			"type": schema.StringAttribute{
+				MarkdownDescription: "Kind of group, `address` or `network`",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					stringvalidator.OneOf(firewallGroupMemberTypes...),
+				},
+			},
+			"member": schema.StringAttribute{
+				MarkdownDescription: "IPv4 address or range such as `192.0.2.1-192.0.2.9` for an address group, or IPv4 prefix for a network group",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+		},
+
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Create: true,
+				Read:   true,
+				Delete: true,
+			}),
+		},
+	}
+}
+
+func (r *FirewallGroupMemberResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	vyosConfig, ok := req.ProviderData.(*vyos.VyosConfig)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *vyos.VyosConfig, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.vyosConfig = vyosConfig
+}
+
+// ValidateConfig checks the member against the configuration schema of the
+// router version.
+func (r *FirewallGroupMemberResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var data FirewallGroupMemberResourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if data.Type.IsNull() || data.Type.IsUnknown() || data.Member.IsNull() || data.Member.IsUnknown() {
+		return
+	}
+
+	version := vyos.Version{}
+	if r.vyosConfig != nil {
+		version = r.vyosConfig.Version(ctx)
+	}
+
+	resp.Diagnostics.Append(validateFirewallGroupMember(version, data.Type.ValueString(), data.Group.ValueString(), data.Member.ValueString(), path.Root("member"))...)
+}
+
+func (r *FirewallGroupMemberResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data *FirewallGroupMemberResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	createTimeout, diags := data.Timeouts.Create(ctx, defaultConfigTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, createTimeout)
+	defer cancel()
+
+	components := data.path()
+
+	// A member added by another configuration belongs to it, deleting this
+	// resource would remove it for both.
+	exists, err := r.exists(ctx, components, data.Member.ValueString())
+	if err != nil {
+		addVyosError(&resp.Diagnostics, path.Root("member"), err)
+		return
+	}
+	if exists {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("member"),
+			"Configuration already exists",
+			fmt.Sprintf("'%s' already has the member %s, try a resource import instead.", vyos.FormatPath(components), data.Member.ValueString()),
+		)
+		return
+	}
+
+	tflog.Info(ctx, fmt.Sprintf("Adding %s to %s", data.Member.ValueString(), vyos.FormatPath(components)))
+
+	if err := r.vyosConfig.AddValue(ctx, components, data.Member.ValueString()); err != nil {
+		addVyosError(&resp.Diagnostics, path.Root("member"), err)
+		return
+	}
+
+	data.Id = types.StringValue(data.id())
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *FirewallGroupMemberResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data *FirewallGroupMemberResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	readTimeout, diags := data.Timeouts.Read(ctx, defaultConfigTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, readTimeout)
+	defer cancel()
+
+	components := data.path()
+	tflog.Info(ctx, fmt.Sprintf("Reading %s in %s", data.Member.ValueString(), vyos.FormatPath(components)))
+
+	exists, err := r.exists(ctx, components, data.Member.ValueString())
+	if err != nil {
+		addVyosError(&resp.Diagnostics, path.Root("id"), err)
+		return
+	}
+	if !exists {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	data.Id = types.StringValue(data.id())
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// Update only changes timeouts, every other attribute replaces the member.
+func (r *FirewallGroupMemberResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data *FirewallGroupMemberResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *FirewallGroupMemberResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data *FirewallGroupMemberResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	deleteTimeout, diags := data.Timeouts.Delete(ctx, defaultConfigTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, deleteTimeout)
+	defer cancel()
+
+	components := data.path()
+	tflog.Info(ctx, fmt.Sprintf("Removing %s from %s", data.Member.ValueString(), vyos.FormatPath(components)))
+
+	if err := r.vyosConfig.RemoveValue(ctx, components, data.Member.ValueString()); err != nil {
+		addVyosError(&resp.Diagnostics, path.Root("id"), err)
+		return
+	}
+}
+
+// ImportState imports a member by its type, group and value separated by
+// slashes, such as `network/ADMIN/192.0.2.0/24`.
+func (r *FirewallGroupMemberResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	parts := strings.SplitN(req.ID, "/", 3)
+	if len(parts) != 3 || (parts[0] != "address" && parts[0] != "network") || parts[1] == "" || parts[2] == "" {
+		resp.Diagnostics.AddError(
+			"Invalid import ID",
+			fmt.Sprintf("Expected the type, group and member separated by slashes, such as network/ADMIN/192.0.2.0/24, got %q.", req.ID),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("type"), parts[0])...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("group"), parts[1])...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("member"), parts[2])...)
+}
+
+// exists reports whether the leaf at components has member.
+func (r *FirewallGroupMemberResource) exists(ctx context.Context, components []string, member string) (bool, error) {
+	config, err := r.vyosConfig.Show(ctx, components)
+	if err != nil || config == nil {
+		return false, err
+	}
+	for _, leaf := range vyos.Leaves(vyos.Normalize(config)) {
+		if leaf.Value == member {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// path returns the path of the leaf holding the members of the group.
+func (m *FirewallGroupMemberResourceModel) path() []string {
+	return append(firewallGroupPath(m.Type.ValueString(), m.Group.ValueString()), m.Type.ValueString())
+}
+
+func (m *FirewallGroupMemberResourceModel) id() string {
+	return strings.Join([]string{m.Type.ValueString(), m.Group.ValueString(), m.Member.ValueString()}, "/")
+}
@@ -0,0 +1,109 @@
+package provider
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccFirewallGroupMemberResource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			if server := testAccPreCheck(t); server != nil {
+				server.SetMulti("network")
+			}
+		},
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing, next to a member added elsewhere
+			{
+				Config: testAccFirewallGroupMemberResourceConfig + `
+resource "vyos_firewall_group_member" "vpn" {
+  group  = "ADMIN"
+  type   = "network"
+  member = "198.51.100.0/24"
+
+  depends_on = [vyos_firewall_group_member.office]
+}
+
+data "vyos_config" "test" {
+  path = "firewall group network-group ADMIN"
+
+  depends_on = [vyos_firewall_group_member.vpn]
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("vyos_firewall_group_member.office", "id", "network/ADMIN/192.0.2.0/24"),
+					resource.TestCheckResourceAttr("data.vyos_config.test", "value", `{"description":"Administrators","network":["203.0.113.0/24","192.0.2.0/24","198.51.100.0/24"]}`),
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:            "vyos_firewall_group_member.office",
+				ImportState:             true,
+				ImportStateId:           "network/ADMIN/192.0.2.0/24",
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{},
+			},
+			// Removing a member leaves the others alone, which the data source
+			// sees once the member is destroyed
+			{
+				Config: testAccFirewallGroupMemberResourceConfig + testAccFirewallGroupMemberDataSourceConfig,
+			},
+			{
+				Config: testAccFirewallGroupMemberResourceConfig + testAccFirewallGroupMemberDataSourceConfig,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.vyos_config.test", "value", `{"description":"Administrators","network":["203.0.113.0/24","192.0.2.0/24"]}`),
+				),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func TestAccFirewallGroupMemberResourceValidation(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "vyos_firewall_group_member" "test" {
+  group  = "ADMIN"
+  type   = "address"
+  member = "192.0.2.0/24"
+}
+`,
+				ExpectError: regexp.MustCompile(`Must\s+be\s+an\s+IPv4\s+address\s+or\s+range`),
+			},
+		},
+	})
+}
+
+// testAccFirewallGroupMemberResourceConfig shares a group between
+// vyos_config_commands, standing in for another configuration, and a member.
+const testAccFirewallGroupMemberResourceConfig = `
+resource "vyos_config_commands" "shared" {
+  commands = [
+    "set firewall group network-group ADMIN description Administrators",
+    "set firewall group network-group ADMIN network 203.0.113.0/24",
+  ]
+}
+
+resource "vyos_firewall_group_member" "office" {
+  group  = "ADMIN"
+  type   = "network"
+  member = "192.0.2.0/24"
+
+  depends_on = [vyos_config_commands.shared]
+}
+`
+
+const testAccFirewallGroupMemberDataSourceConfig = `
+data "vyos_config" "test" {
+  path = "firewall group network-group ADMIN"
+
+  depends_on = [vyos_firewall_group_member.office]
+}
+`
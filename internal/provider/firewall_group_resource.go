@@ -0,0 +1,274 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/TGNThump/terraform-provider-vyos/internal/vyos"
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/setvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces
+var _ resource.Resource = &FirewallGroupResource{}
+var _ resource.ResourceWithImportState = &FirewallGroupResource{}
+var _ resource.ResourceWithConfigure = &FirewallGroupResource{}
+var _ resource.ResourceWithValidateConfig = &FirewallGroupResource{}
+
+func NewFirewallGroupResource() resource.Resource {
+	return &FirewallGroupResource{
+		subtreeResource[FirewallGroupResourceModel, *FirewallGroupResourceModel]{kind: "firewall group", keyAttribute: "name"},
+	}
+}
+
+// FirewallGroupResource manages a firewall group and all of its members.
+type FirewallGroupResource struct {
+	subtreeResource[FirewallGroupResourceModel, *FirewallGroupResourceModel]
+}
+
+// FirewallGroupResourceModel describes the resource data model.
+type FirewallGroupResourceModel struct {
+	Id          types.String   `tfsdk:"id"`
+	Name        types.String   `tfsdk:"name"`
+	Type        types.String   `tfsdk:"type"`
+	Description types.String   `tfsdk:"description"`
+	Members     []types.String `tfsdk:"members"`
+
+	Timeouts timeouts.Value `tfsdk:"timeouts"`
+}
+
+// firewallGroupTypes are the kinds of firewall group which can be managed.
+// The members of a group are the values of the leaf named after its kind,
+// such as `address` for `firewall group address-group`.
+var firewallGroupTypes = []string{"address", "network", "port", "interface"}
+
+var firewallGroupNameValidator = stringvalidator.RegexMatches(
+	regexp.MustCompile(`^[^-\s][^\s]{0,30}$`),
+	"must be at most 31 characters without spaces, not starting with a hyphen",
+)
+
+// firewallGroupPath returns the configuration path of the group name of
+// kind groupType.
+func firewallGroupPath(groupType string, name string) []string {
+	return []string{"firewall", "group", groupType + "-group", name}
+}
+
+// requireFirewallGroupType returns an error when the router version has no
+// groups of kind groupType.
+func requireFirewallGroupType(version vyos.Version, groupType string) error {
+	if groupType == "interface" {
+		return version.Require(vyos.FeatureFirewallInterfaceGroups)
+	}
+	return nil
+}
+
+func (r *FirewallGroupResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_firewall_group"
+}
+
+func (r *FirewallGroupResource) Schema(ctx context.Context, request resource.SchemaRequest, response *resource.SchemaResponse) {
+	response.Schema = schema.Schema{
+		MarkdownDescription: "A firewall group, such as `firewall group address-group ADMIN`. The resource manages every member of the " +
+			"group and removes members added elsewhere; use `vyos_firewall_group_member` instead to share a group between " +
+			"several configurations.",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Configuration path of the group",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"name": schema.StringAttribute{
+				MarkdownDescription: "Name of the group",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					firewallGroupNameValidator,
+				},
+			},
+			"type": schema.StringAttribute{
+				MarkdownDescription: "Kind of group, `address`, `network`, `port` or `interface`. Interface groups require VyOS 1.4 or later.",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					stringvalidator.OneOf(firewallGroupTypes...),
+				},
+			},
+			"description": schema.StringAttribute{
+				MarkdownDescription: "Description",
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtMost(256),
+				},
+			},
+			"members": schema.SetAttribute{
+				MarkdownDescription: "Members of the group: IPv4 addresses or ranges such as `192.0.2.1-192.0.2.9` for address groups, " +
+					"IPv4 prefixes for network groups, ports, port ranges or service names for port groups, and interface names " +
+					"for interface groups. Changing the set only adds and removes the members which changed.",
+				Optional:    true,
+				ElementType: types.StringType,
+				Validators: []validator.Set{
+					setvalidator.SizeAtLeast(1),
+				},
+			},
+		},
+
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Create: true,
+				Read:   true,
+				Update: true,
+				Delete: true,
+			}),
+		},
+	}
+}
+
+// ValidateConfig checks the group type and members against the router
+// version, so an IPv6 address in an IPv4 group is caught when planning.
+func (r *FirewallGroupResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var groupType, name types.String
+	var members types.Set
+
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("type"), &groupType)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("name"), &name)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("members"), &members)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if groupType.IsNull() || groupType.IsUnknown() {
+		return
+	}
+
+	version := vyos.Version{}
+	if r.vyosConfig != nil {
+		version = r.vyosConfig.Version(ctx)
+	}
+
+	if err := requireFirewallGroupType(version, groupType.ValueString()); err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("type"), "Unsupported firewall group type", err.Error())
+		return
+	}
+
+	if members.IsNull() || members.IsUnknown() {
+		return
+	}
+
+	resp.Diagnostics.Append(validateFirewallGroupMembers(version, groupType.ValueString(), name.ValueString(), members, path.Root("members"))...)
+}
+
+// ImportState imports a group by its type and name, such as
+// `address/ADMIN`.
+func (r *FirewallGroupResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	groupType, name, ok := strings.Cut(req.ID, "/")
+	if !ok || !isFirewallGroupType(groupType) || name == "" {
+		resp.Diagnostics.AddError(
+			"Invalid import ID",
+			fmt.Sprintf("Expected the type and name of the group separated by a slash, such as address/ADMIN, got %q.", req.ID),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("type"), groupType)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("name"), name)...)
+}
+
+func (m *FirewallGroupResourceModel) path(version vyos.Version) []string {
+	return firewallGroupPath(m.Type.ValueString(), m.Name.ValueString())
+}
+
+func (m *FirewallGroupResourceModel) require(version vyos.Version) diag.Diagnostics {
+	var diags diag.Diagnostics
+	if err := requireFirewallGroupType(version, m.Type.ValueString()); err != nil {
+		diags.AddAttributeError(path.Root("type"), "Unsupported firewall group type", err.Error())
+	}
+	return diags
+}
+
+// config returns the configuration tree of the group.
+func (m *FirewallGroupResourceModel) config(version vyos.Version) map[string]any {
+	tree := map[string]any{}
+	setTypedString(tree, "description", m.Description)
+	if len(m.Members) > 0 {
+		members := []any{}
+		for _, member := range m.Members {
+			members = append(members, member.ValueString())
+		}
+		tree[m.Type.ValueString()] = members
+	}
+	return tree
+}
+
+func (m *FirewallGroupResourceModel) read(config any) {
+	m.Description = typedString(config, "description")
+
+	// A group holding a single member returns it as a string rather than a
+	// list, which typedStrings reads the same way.
+	m.Members = nil
+	for _, member := range typedStrings(config, m.Type.ValueString()) {
+		m.Members = append(m.Members, types.StringValue(member))
+	}
+}
+
+func isFirewallGroupType(groupType string) bool {
+	for _, known := range firewallGroupTypes {
+		if groupType == known {
+			return true
+		}
+	}
+	return false
+}
+
+// validateFirewallGroupMembers checks the known values of members against
+// the configuration schema of version, reporting errors on attribute.
+func validateFirewallGroupMembers(version vyos.Version, groupType string, name string, members types.Set, attribute path.Path) diag.Diagnostics {
+	var diags diag.Diagnostics
+	for _, element := range members.Elements() {
+		member, ok := element.(types.String)
+		if !ok || member.IsNull() || member.IsUnknown() {
+			continue
+		}
+		diags.Append(validateFirewallGroupMember(version, groupType, name, member.ValueString(), attribute.AtSetValue(member))...)
+	}
+	return diags
+}
+
+// validateFirewallGroupMember checks member against the configuration
+// schema of version, reporting errors on attribute.
+func validateFirewallGroupMember(version vyos.Version, groupType string, name string, member string, attribute path.Path) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	// Unknown names are checked by their validator, any valid one will do.
+	if name == "" {
+		name = "GROUP"
+	}
+	components := append(firewallGroupPath(groupType, name), groupType)
+
+	schemaErrs, err := vyos.ValidateSchema(version, components, member)
+	if err != nil {
+		diags.AddError("Unable to load the VyOS configuration schema", err.Error())
+		return diags
+	}
+	for _, schemaErr := range schemaErrs {
+		diags.AddAttributeError(attribute, "Invalid firewall group member", schemaErr.Error())
+	}
+	return diags
+}
@@ -0,0 +1,147 @@
+package provider
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"testing"
+
+	"github.com/TGNThump/terraform-provider-vyos/internal/vyostest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccFirewallGroupResource(t *testing.T) {
+	var server *vyostest.Server
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			if server = testAccPreCheck(t); server != nil {
+				server.SetMulti("address")
+			}
+		},
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: testAccFirewallGroupResourceConfig(`["192.0.2.1", "192.0.2.2"]`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("vyos_firewall_group.test", "id", "firewall group address-group ADMIN"),
+					resource.TestCheckResourceAttr("vyos_firewall_group.test", "members.#", "2"),
+					resource.TestCheckResourceAttr("data.vyos_config.test", "value", `{"address":["192.0.2.1","192.0.2.2"],"description":"Administrators"}`),
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:            "vyos_firewall_group.test",
+				ImportState:             true,
+				ImportStateId:           "address/ADMIN",
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{},
+			},
+			// Update and Read testing, only the changed members are sent
+			{
+				Config: testAccFirewallGroupResourceConfig(`["192.0.2.1", "192.0.2.3"]`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("vyos_firewall_group.test", "members.#", "2"),
+					resource.TestCheckResourceAttr("data.vyos_config.test", "value", `{"address":["192.0.2.1","192.0.2.3"],"description":"Administrators"}`),
+					testAccCheckLastConfigure(&server, []any{
+						map[string]any{"op": "delete", "path": []any{"firewall", "group", "address-group", "ADMIN", "address"}, "value": "192.0.2.2"},
+						map[string]any{"op": "set", "path": []any{"firewall", "group", "address-group", "ADMIN", "address"}, "value": "192.0.2.3"},
+					}),
+				),
+			},
+			// A single member reads the same as several
+			{
+				Config: testAccFirewallGroupResourceConfig(`["192.0.2.1"]`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("vyos_firewall_group.test", "members.#", "1"),
+					resource.TestCheckResourceAttr("vyos_firewall_group.test", "members.0", "192.0.2.1"),
+				),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func TestAccFirewallGroupResourceValidation(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "vyos_firewall_group" "test" {
+  name    = "ADMIN"
+  type    = "network"
+  members = ["2001:db8::/32"]
+}
+`,
+				ExpectError: regexp.MustCompile(`Must\s+be\s+an\s+IPv4\s+prefix`),
+			},
+		},
+	})
+}
+
+func TestAccFirewallGroupResourceInterfaceVyos13(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			server := testAccPreCheck(t)
+			if server == nil {
+				t.Skip("older VyOS versions are only tested against the fake server")
+			}
+			server.SetVersion("1.3.8")
+		},
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "vyos_firewall_group" "test" {
+  name    = "LAN"
+  type    = "interface"
+  members = ["eth1"]
+}
+`,
+				ExpectError: regexp.MustCompile(`firewall\s+interface\s+groups\s+requires\s+VyOS\s+1.4\s+or\s+later`),
+			},
+		},
+	})
+}
+
+// testAccCheckLastConfigure checks the operations of the last configure
+// request the fake server received. It passes against a real router.
+func testAccCheckLastConfigure(server **vyostest.Server, expected []any) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		if *server == nil {
+			return nil
+		}
+
+		var operations any
+		for _, request := range (*server).Requests() {
+			if request.Endpoint == "configure" {
+				operations = request.Payload
+			}
+		}
+		if !reflect.DeepEqual(operations, expected) {
+			return fmt.Errorf("unexpected operations:\n%v\nexpected\n%v", operations, expected)
+		}
+		return nil
+	}
+}
+
+func testAccFirewallGroupResourceConfig(members string) string {
+	return fmt.Sprintf(`
+resource "vyos_firewall_group" "test" {
+  name        = "ADMIN"
+  type        = "address"
+  description = "Administrators"
+  members     = %[1]s
+}
+
+data "vyos_config" "test" {
+  path = "firewall group address-group ADMIN"
+
+  depends_on = [vyos_firewall_group.test]
+}
+`, members)
+}
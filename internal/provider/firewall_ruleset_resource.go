@@ -0,0 +1,445 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/TGNThump/terraform-provider-vyos/internal/vyos"
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/mapvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/setvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces
+var _ resource.Resource = &FirewallRulesetResource{}
+var _ resource.ResourceWithImportState = &FirewallRulesetResource{}
+var _ resource.ResourceWithConfigure = &FirewallRulesetResource{}
+
+func NewFirewallRulesetResource() resource.Resource {
+	return &FirewallRulesetResource{
+		subtreeResource[FirewallRulesetResourceModel, *FirewallRulesetResourceModel]{kind: "firewall ruleset", keyAttribute: "name", versioned: true},
+	}
+}
+
+// FirewallRulesetResource manages a named firewall ruleset, at `firewall
+// name` and `firewall ipv6-name` on VyOS 1.3 and at `firewall ipv4 name` and
+// `firewall ipv6 name` on 1.4 and later.
+type FirewallRulesetResource struct {
+	subtreeResource[FirewallRulesetResourceModel, *FirewallRulesetResourceModel]
+}
+
+// FirewallRulesetResourceModel describes the resource data model.
+type FirewallRulesetResourceModel struct {
+	Id               types.String                 `tfsdk:"id"`
+	Name             types.String                 `tfsdk:"name"`
+	Family           types.String                 `tfsdk:"family"`
+	DefaultAction    types.String                 `tfsdk:"default_action"`
+	Description      types.String                 `tfsdk:"description"`
+	EnableDefaultLog types.Bool                   `tfsdk:"enable_default_log"`
+	Rule             map[string]FirewallRuleModel `tfsdk:"rule"`
+
+	Timeouts timeouts.Value `tfsdk:"timeouts"`
+}
+
+// FirewallRuleModel describes a rule of a ruleset.
+type FirewallRuleModel struct {
+	Action      types.String            `tfsdk:"action"`
+	Description types.String            `tfsdk:"description"`
+	JumpTarget  types.String            `tfsdk:"jump_target"`
+	Protocol    types.String            `tfsdk:"protocol"`
+	Source      *FirewallRuleMatchModel `tfsdk:"source"`
+	Destination *FirewallRuleMatchModel `tfsdk:"destination"`
+	State       []types.String          `tfsdk:"state"`
+	Log         types.Bool              `tfsdk:"log"`
+}
+
+// FirewallRuleMatchModel describes the source or destination of a rule.
+type FirewallRuleMatchModel struct {
+	Address types.String            `tfsdk:"address"`
+	Port    types.String            `tfsdk:"port"`
+	Group   *FirewallRuleGroupModel `tfsdk:"group"`
+}
+
+// FirewallRuleGroupModel describes the firewall groups a source or
+// destination of a rule matches.
+type FirewallRuleGroupModel struct {
+	AddressGroup types.String `tfsdk:"address_group"`
+	NetworkGroup types.String `tfsdk:"network_group"`
+	PortGroup    types.String `tfsdk:"port_group"`
+}
+
+// firewallRuleStates are the connection states a rule can match.
+var firewallRuleStates = []string{"established", "invalid", "new", "related"}
+
+func (r *FirewallRulesetResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_firewall_ruleset"
+}
+
+func (r *FirewallRulesetResource) Schema(ctx context.Context, request resource.SchemaRequest, response *resource.SchemaResponse) {
+	match := func(direction string) schema.SingleNestedAttribute {
+		return schema.SingleNestedAttribute{
+			MarkdownDescription: fmt.Sprintf("%s of the packets the rule matches", direction),
+			Optional:            true,
+			Attributes: map[string]schema.Attribute{
+				"address": schema.StringAttribute{
+					MarkdownDescription: "IP address, prefix or range, such as `192.0.2.0/24`. Prefix with `!` to match any other address.",
+					Optional:            true,
+				},
+				"port": schema.StringAttribute{
+					MarkdownDescription: "Port numbers, ranges or service names, separated by commas, such as `22,80,8000-8080`",
+					Optional:            true,
+				},
+				"group": schema.SingleNestedAttribute{
+					MarkdownDescription: "Firewall groups to match",
+					Optional:            true,
+					Attributes: map[string]schema.Attribute{
+						"address_group": schema.StringAttribute{
+							MarkdownDescription: "Name of an address group",
+							Optional:            true,
+						},
+						"network_group": schema.StringAttribute{
+							MarkdownDescription: "Name of a network group",
+							Optional:            true,
+						},
+						"port_group": schema.StringAttribute{
+							MarkdownDescription: "Name of a port group",
+							Optional:            true,
+						},
+					},
+				},
+			},
+		}
+	}
+
+	response.Schema = schema.Schema{
+		MarkdownDescription: "A named firewall ruleset. The ruleset is written to `firewall name` or `firewall ipv6-name` on VyOS 1.3 " +
+			"and to `firewall ipv4 name` or `firewall ipv6 name` on VyOS 1.4 and later, depending on the router version.",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Configuration path of the ruleset",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"name": schema.StringAttribute{
+				MarkdownDescription: "Name of the ruleset",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(regexp.MustCompile(`^[^-\s][^\s]{0,27}$`), "must be at most 28 characters without spaces, not starting with a hyphen"),
+				},
+			},
+			"family": schema.StringAttribute{
+				MarkdownDescription: "Address family of the ruleset, `ipv4` or `ipv6`. Defaults to `ipv4`.",
+				Optional:            true,
+				Computed:            true,
+				Default:             stringdefault.StaticString("ipv4"),
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					stringvalidator.OneOf("ipv4", "ipv6"),
+				},
+			},
+			"default_action": schema.StringAttribute{
+				MarkdownDescription: "Action for packets no rule matches. VyOS defaults to `drop` when not set.",
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.OneOf("accept", "drop", "jump", "reject", "return"),
+				},
+			},
+			"description": schema.StringAttribute{
+				MarkdownDescription: "Description",
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtMost(256),
+				},
+			},
+			"enable_default_log": schema.BoolAttribute{
+				MarkdownDescription: "Log packets hitting the default action",
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(false),
+			},
+			"rule": schema.MapNestedAttribute{
+				MarkdownDescription: "Rules of the ruleset, keyed by rule number",
+				Optional:            true,
+				Validators: []validator.Map{
+					mapvalidator.SizeAtLeast(1),
+					mapvalidator.KeysAre(stringvalidator.RegexMatches(regexp.MustCompile(`^[1-9][0-9]{0,5}$`), "must be a rule number between 1 and 999999")),
+				},
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"action": schema.StringAttribute{
+							MarkdownDescription: "Action for matching packets, such as `accept`, `drop`, `reject` or `jump`",
+							Required:            true,
+							Validators: []validator.String{
+								stringvalidator.OneOf("accept", "continue", "drop", "inspect", "jump", "queue", "reject", "return", "synproxy"),
+							},
+						},
+						"description": schema.StringAttribute{
+							MarkdownDescription: "Description",
+							Optional:            true,
+							Validators: []validator.String{
+								stringvalidator.LengthAtMost(256),
+							},
+						},
+						"jump_target": schema.StringAttribute{
+							MarkdownDescription: "Ruleset to jump to when `action` is `jump`",
+							Optional:            true,
+						},
+						"protocol": schema.StringAttribute{
+							MarkdownDescription: "Protocol to match, by name or number, such as `tcp`, `udp`, `tcp_udp` or `all`. Prefix with `!` to match any other protocol.",
+							Optional:            true,
+						},
+						"source":      match("Source"),
+						"destination": match("Destination"),
+						"state": schema.SetAttribute{
+							MarkdownDescription: "Connection states to match, of `established`, `invalid`, `new` and `related`",
+							Optional:            true,
+							ElementType:         types.StringType,
+							Validators: []validator.Set{
+								setvalidator.SizeAtLeast(1),
+								setvalidator.ValueStringsAre(stringvalidator.OneOf(firewallRuleStates...)),
+							},
+						},
+						"log": schema.BoolAttribute{
+							MarkdownDescription: "Log packets matching the rule",
+							Optional:            true,
+							Computed:            true,
+							Default:             booldefault.StaticBool(false),
+						},
+					},
+				},
+			},
+		},
+
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Create: true,
+				Read:   true,
+				Update: true,
+				Delete: true,
+			}),
+		},
+	}
+}
+
+// ImportState imports a ruleset by its name, such as `WAN_LOCAL`, prefixed
+// with `ipv6/` for IPv6 rulesets.
+func (r *FirewallRulesetResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	family, name := "ipv4", req.ID
+	if prefix, rest, ok := strings.Cut(req.ID, "/"); ok {
+		family, name = prefix, rest
+	}
+	if (family != "ipv4" && family != "ipv6") || name == "" {
+		resp.Diagnostics.AddError(
+			"Invalid import ID",
+			fmt.Sprintf("Expected the name of the ruleset, prefixed with ipv4/ or ipv6/ for a specific family, such as WAN_LOCAL or ipv6/WAN_LOCAL, got %q.", req.ID),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("name"), name)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("family"), family)...)
+}
+
+func (m *FirewallRulesetResourceModel) path(version vyos.Version) []string {
+	// Imported rulesets have no family yet.
+	family := m.Family.ValueString()
+	if m.Family.IsNull() {
+		family = "ipv4"
+	}
+	return version.FirewallNamePath(family, m.Name.ValueString())
+}
+
+// config returns the configuration tree of the ruleset in the layout of
+// version.
+func (m *FirewallRulesetResourceModel) config(version vyos.Version) map[string]any {
+	tree := map[string]any{}
+	setTypedString(tree, "default_action", m.DefaultAction)
+	setTypedString(tree, "description", m.Description)
+	if m.EnableDefaultLog.ValueBool() {
+		tree[firewallDefaultLogNode(version)] = map[string]any{}
+	}
+
+	if len(m.Rule) > 0 {
+		rules := map[string]any{}
+		for number, rule := range m.Rule {
+			rules[number] = rule.config(version)
+		}
+		tree["rule"] = rules
+	}
+	return tree
+}
+
+func (m FirewallRuleModel) config(version vyos.Version) map[string]any {
+	tree := map[string]any{}
+	setTypedString(tree, "action", m.Action)
+	setTypedString(tree, "description", m.Description)
+	setTypedString(tree, "jump_target", m.JumpTarget)
+	setTypedString(tree, "protocol", m.Protocol)
+	if match := m.Source.config(); len(match) > 0 {
+		tree["source"] = match
+	}
+	if match := m.Destination.config(); len(match) > 0 {
+		tree["destination"] = match
+	}
+
+	// VyOS 1.4 turned `state established enable` into `state established`.
+	if len(m.State) > 0 {
+		states := []any{}
+		enabled := map[string]any{}
+		for _, state := range m.State {
+			states = append(states, state.ValueString())
+			enabled[state.ValueString()] = "enable"
+		}
+		if version.Supports(vyos.FeatureFirewallFamilies) {
+			tree["state"] = states
+		} else {
+			tree["state"] = enabled
+		}
+	}
+	if m.Log.ValueBool() {
+		tree["log"] = ruleLogConfig(version)
+	}
+	return tree
+}
+
+func (m *FirewallRuleMatchModel) config() map[string]any {
+	tree := map[string]any{}
+	if m == nil {
+		return tree
+	}
+	setTypedString(tree, "address", m.Address)
+	setTypedString(tree, "port", m.Port)
+	if m.Group != nil {
+		group := map[string]any{}
+		setTypedString(group, "address_group", m.Group.AddressGroup)
+		setTypedString(group, "network_group", m.Group.NetworkGroup)
+		setTypedString(group, "port_group", m.Group.PortGroup)
+		if len(group) > 0 {
+			tree["group"] = group
+		}
+	}
+	return tree
+}
+
+// read sets the model to the ruleset configuration config, in the layout of
+// any VyOS version.
+func (m *FirewallRulesetResourceModel) read(config any) {
+	if m.Family.IsNull() {
+		m.Family = types.StringValue("ipv4")
+	}
+	m.DefaultAction = typedString(config, "default_action")
+	m.Description = typedString(config, "description")
+	m.EnableDefaultLog = types.BoolValue(typedChild(config, "enable_default_log") != nil || typedChild(config, "default_log") != nil)
+
+	rules, _ := typedChild(config, "rule").(map[string]any)
+	if len(rules) == 0 {
+		m.Rule = nil
+		return
+	}
+	m.Rule = map[string]FirewallRuleModel{}
+	for number, rule := range rules {
+		m.Rule[number] = readFirewallRule(rule)
+	}
+}
+
+// readFirewallRule returns the rule configured as config, reading `state`
+// and `log` in the layout of either VyOS version.
+func readFirewallRule(config any) FirewallRuleModel {
+	rule := FirewallRuleModel{
+		Action:      typedString(config, "action"),
+		Description: typedString(config, "description"),
+		JumpTarget:  typedString(config, "jump_target"),
+		Protocol:    typedString(config, "protocol"),
+		Source:      readFirewallRuleMatch(typedChild(config, "source")),
+		Destination: readFirewallRuleMatch(typedChild(config, "destination")),
+	}
+
+	rule.Log = readRuleLog(config)
+
+	var states []string
+	if enabled, ok := typedChild(config, "state").(map[string]any); ok {
+		for state, value := range enabled {
+			if value == "enable" {
+				states = append(states, state)
+			}
+		}
+		sort.Strings(states)
+	} else {
+		states = typedStrings(config, "state")
+	}
+	for _, state := range states {
+		rule.State = append(rule.State, types.StringValue(state))
+	}
+
+	return rule
+}
+
+func readFirewallRuleMatch(config any) *FirewallRuleMatchModel {
+	if config == nil {
+		return nil
+	}
+
+	match := &FirewallRuleMatchModel{
+		Address: typedString(config, "address"),
+		Port:    typedString(config, "port"),
+	}
+	if group := typedChild(config, "group"); group != nil {
+		match.Group = &FirewallRuleGroupModel{
+			AddressGroup: typedString(group, "address_group"),
+			NetworkGroup: typedString(group, "network_group"),
+			PortGroup:    typedString(group, "port_group"),
+		}
+	}
+	return match
+}
+
+// firewallDefaultLogNode returns the node logging the default action of a
+// ruleset, which VyOS 1.4 renamed from `enable-default-log` to `default-log`.
+func firewallDefaultLogNode(version vyos.Version) string {
+	if version.Supports(vyos.FeatureFirewallFamilies) {
+		return "default-log"
+	}
+	return "enable-default-log"
+}
+
+// ruleLogConfig returns the configuration of the `log` node enabling logging
+// of a firewall or NAT rule, which VyOS 1.4 made valueless.
+func ruleLogConfig(version vyos.Version) any {
+	if version.Supports(vyos.FeatureValuelessLog) {
+		return map[string]any{}
+	}
+	return "enable"
+}
+
+// readRuleLog reads the `log` node of a firewall or NAT rule in the layout of
+// either VyOS version.
+func readRuleLog(config any) types.Bool {
+	switch log := typedChild(config, "log").(type) {
+	case nil:
+		return types.BoolValue(false)
+	case string:
+		return types.BoolValue(log == "enable")
+	}
+	return types.BoolValue(true)
+}
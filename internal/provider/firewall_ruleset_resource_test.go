@@ -0,0 +1,162 @@
+package provider
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccFirewallRulesetResource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			if server := testAccPreCheck(t); server != nil {
+				server.SetMulti("state")
+			}
+		},
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: testAccFirewallRulesetResourceConfig("ipv4", "firewall ipv4 name WAN_LOCAL", `
+    20 = {
+      action   = "accept"
+      protocol = "tcp"
+      destination = {
+        port = "22"
+      }
+      source = {
+        group = {
+          network_group = "ADMIN"
+        }
+      }
+      log = true
+    }
+`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("vyos_firewall_ruleset.test", "id", "firewall ipv4 name WAN_LOCAL"),
+					resource.TestCheckResourceAttr("vyos_firewall_ruleset.test", "rule.%", "2"),
+					resource.TestCheckResourceAttr("vyos_firewall_ruleset.test", "rule.10.state.#", "2"),
+					resource.TestCheckResourceAttr("data.vyos_config.test", "value", `{"default-action":"drop","default-log":{},"description":"Traffic to the router",`+
+						`"rule":{"10":{"action":"accept","state":["established","related"]},`+
+						`"20":{"action":"accept","destination":{"port":"22"},"log":{},"protocol":"tcp","source":{"group":{"network-group":"ADMIN"}}}}}`),
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:            "vyos_firewall_ruleset.test",
+				ImportState:             true,
+				ImportStateId:           "WAN_LOCAL",
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{},
+			},
+			// Update and Read testing, the removed rule is deleted
+			{
+				Config: testAccFirewallRulesetResourceConfig("ipv4", "firewall ipv4 name WAN_LOCAL", `
+    30 = {
+      action = "drop"
+      source = {
+        address = "198.51.100.0/24"
+      }
+    }
+`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("vyos_firewall_ruleset.test", "rule.%", "2"),
+					resource.TestCheckNoResourceAttr("vyos_firewall_ruleset.test", "rule.20.action"),
+					resource.TestCheckResourceAttr("data.vyos_config.test", "value", `{"default-action":"drop","default-log":{},"description":"Traffic to the router",`+
+						`"rule":{"10":{"action":"accept","state":["established","related"]},"30":{"action":"drop","source":{"address":"198.51.100.0/24"}}}}`),
+				),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func TestAccFirewallRulesetResourceVyos13(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			server := testAccPreCheck(t)
+			if server == nil {
+				t.Skip("the VyOS 1.3 layout is only tested against the fake router")
+			}
+			server.SetVersion("1.3.8")
+		},
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: testAccFirewallRulesetResourceConfig("ipv6", "firewall ipv6-name WAN_LOCAL", `
+    20 = {
+      action   = "accept"
+      protocol = "ipv6-icmp"
+      log      = true
+    }
+`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("vyos_firewall_ruleset.test", "id", "firewall ipv6-name WAN_LOCAL"),
+					resource.TestCheckResourceAttr("vyos_firewall_ruleset.test", "rule.10.state.#", "2"),
+					resource.TestCheckResourceAttr("vyos_firewall_ruleset.test", "rule.20.log", "true"),
+					resource.TestCheckResourceAttr("data.vyos_config.test", "value", `{"default-action":"drop","description":"Traffic to the router","enable-default-log":{},`+
+						`"rule":{"10":{"action":"accept","state":{"established":"enable","related":"enable"}},"20":{"action":"accept","log":"enable","protocol":"ipv6-icmp"}}}`),
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:            "vyos_firewall_ruleset.test",
+				ImportState:             true,
+				ImportStateId:           "ipv6/WAN_LOCAL",
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{},
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func TestAccFirewallRulesetResourceUnknownVersion(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			server := testAccPreCheck(t)
+			if server == nil {
+				t.Skip("failing version detection is only tested against the fake router")
+			}
+			server.SetVersion("1.3.8")
+			server.FailNext("show", http.StatusBadRequest, "Invalid command: show version")
+		},
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// The ruleset is not written in the layout of a guessed version
+			{
+				Config:      testAccFirewallRulesetResourceConfig("ipv4", "firewall name WAN_LOCAL", ""),
+				ExpectError: regexp.MustCompile(`Unable to detect the VyOS version`),
+			},
+		},
+	})
+}
+
+func testAccFirewallRulesetResourceConfig(family string, path string, rules string) string {
+	return fmt.Sprintf(`
+resource "vyos_firewall_ruleset" "test" {
+  name               = "WAN_LOCAL"
+  family             = %[1]q
+  default_action     = "drop"
+  description        = "Traffic to the router"
+  enable_default_log = true
+
+  rule = {
+    10 = {
+      action = "accept"
+      state  = ["established", "related"]
+    }
+%[3]s  }
+}
+
+data "vyos_config" "test" {
+  path = %[2]q
+
+  depends_on = [vyos_firewall_ruleset.test]
+}
+`, family, path, rules)
+}
@@ -0,0 +1,330 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/TGNThump/terraform-provider-vyos/internal/vyos"
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces
+var _ resource.Resource = &NatRuleResource{}
+var _ resource.ResourceWithImportState = &NatRuleResource{}
+var _ resource.ResourceWithConfigure = &NatRuleResource{}
+var _ resource.ResourceWithValidateConfig = &NatRuleResource{}
+
+func NewNatRuleResource() resource.Resource {
+	return &NatRuleResource{
+		subtreeResource[NatRuleResourceModel, *NatRuleResourceModel]{kind: "NAT rule", keyAttribute: "rule", versioned: true},
+	}
+}
+
+// NatRuleResource manages a single NAT rule, such as `nat source rule 100`.
+type NatRuleResource struct {
+	subtreeResource[NatRuleResourceModel, *NatRuleResourceModel]
+}
+
+// NatRuleResourceModel describes the resource data model.
+type NatRuleResourceModel struct {
+	Id                types.String    `tfsdk:"id"`
+	Type              types.String    `tfsdk:"type"`
+	Rule              types.Int64     `tfsdk:"rule"`
+	Description       types.String    `tfsdk:"description"`
+	InboundInterface  types.String    `tfsdk:"inbound_interface"`
+	OutboundInterface types.String    `tfsdk:"outbound_interface"`
+	Protocol          types.String    `tfsdk:"protocol"`
+	Source            *NatRuleAddress `tfsdk:"source"`
+	Destination       *NatRuleAddress `tfsdk:"destination"`
+	Translation       *NatRuleAddress `tfsdk:"translation"`
+	Exclude           types.Bool      `tfsdk:"exclude"`
+	Log               types.Bool      `tfsdk:"log"`
+
+	Timeouts timeouts.Value `tfsdk:"timeouts"`
+}
+
+// NatRuleAddress describes the source, destination or translation of a NAT
+// rule.
+type NatRuleAddress struct {
+	Address types.String `tfsdk:"address"`
+	Port    types.String `tfsdk:"port"`
+}
+
+// natRuleTypes are the kinds of NAT rule, the `nat66-` ones being IPv6
+// network prefix translation rules under `nat66`.
+var natRuleTypes = []string{"source", "destination", "static", "nat66-source", "nat66-destination"}
+
+// natRulePath returns the configuration path of rule number of kind
+// ruleType.
+func natRulePath(ruleType string, number int64) []string {
+	rule := strconv.FormatInt(number, 10)
+	if strings.HasPrefix(ruleType, "nat66-") {
+		return []string{"nat66", strings.TrimPrefix(ruleType, "nat66-"), "rule", rule}
+	}
+	return []string{"nat", ruleType, "rule", rule}
+}
+
+func (r *NatRuleResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_nat_rule"
+}
+
+func (r *NatRuleResource) Schema(ctx context.Context, request resource.SchemaRequest, response *resource.SchemaResponse) {
+	address := func(description string, addressDescription string) schema.SingleNestedAttribute {
+		return schema.SingleNestedAttribute{
+			MarkdownDescription: description,
+			Optional:            true,
+			Attributes: map[string]schema.Attribute{
+				"address": schema.StringAttribute{
+					MarkdownDescription: addressDescription,
+					Optional:            true,
+				},
+				"port": schema.StringAttribute{
+					MarkdownDescription: "Port numbers, ranges or service names, separated by commas, such as `22,80,8000-8080`",
+					Optional:            true,
+				},
+			},
+		}
+	}
+
+	response.Schema = schema.Schema{
+		MarkdownDescription: "A NAT rule, such as `nat source rule 100`.",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Configuration path of the rule",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"type": schema.StringAttribute{
+				MarkdownDescription: "Kind of rule: `source`, `destination` or `static` for the rules under `nat`, and `nat66-source` or " +
+					"`nat66-destination` for the IPv6 prefix translation rules under `nat66`. Static rules require VyOS 1.4 or later.",
+				Required: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					stringvalidator.OneOf(natRuleTypes...),
+				},
+			},
+			"rule": schema.Int64Attribute{
+				MarkdownDescription: "Rule number",
+				Required:            true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplace(),
+				},
+				Validators: []validator.Int64{
+					int64validator.Between(1, 999999),
+				},
+			},
+			"description": schema.StringAttribute{
+				MarkdownDescription: "Description",
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtMost(256),
+				},
+			},
+			"inbound_interface": schema.StringAttribute{
+				MarkdownDescription: "Interface the packets arrive on, for destination and static rules",
+				Optional:            true,
+			},
+			"outbound_interface": schema.StringAttribute{
+				MarkdownDescription: "Interface the packets leave through, for source rules",
+				Optional:            true,
+			},
+			"protocol": schema.StringAttribute{
+				MarkdownDescription: "Protocol to match, by name or number, such as `tcp`, `udp`, `tcp_udp` or `all`. Prefix with `!` to match any other protocol.",
+				Optional:            true,
+			},
+			"source":      address("Source of the packets the rule matches", "IP address, prefix or range, such as `192.0.2.0/24`. Prefix with `!` to match any other address."),
+			"destination": address("Destination of the packets the rule matches", "IP address, prefix or range, such as `192.0.2.0/24`. Prefix with `!` to match any other address."),
+			"translation": address("What the matching packets are translated to", "Address, prefix or range to translate to, or `masquerade` to use the address of the outbound interface in source rules"),
+			"exclude": schema.BoolAttribute{
+				MarkdownDescription: "Exclude the matching packets from NAT",
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(false),
+			},
+			"log": schema.BoolAttribute{
+				MarkdownDescription: "Log packets matching the rule",
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(false),
+			},
+		},
+
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Create: true,
+				Read:   true,
+				Update: true,
+				Delete: true,
+			}),
+		},
+	}
+}
+
+// ValidateConfig checks that the router version has rules of the type.
+func (r *NatRuleResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var ruleType types.String
+
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("type"), &ruleType)...)
+	if resp.Diagnostics.HasError() || ruleType.IsNull() || ruleType.IsUnknown() || ruleType.ValueString() != "static" {
+		return
+	}
+
+	if r.vyosConfig == nil {
+		return
+	}
+	if err := r.vyosConfig.Version(ctx).Require(vyos.FeatureStaticNat); err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("type"), "Unsupported NAT rule type", err.Error())
+	}
+}
+
+// ImportState imports a rule by its type and number, such as `source/100`.
+func (r *NatRuleResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	ruleType, rule, _ := strings.Cut(req.ID, "/")
+	number, err := strconv.ParseInt(rule, 10, 64)
+	if err != nil || !isNatRuleType(ruleType) {
+		resp.Diagnostics.AddError(
+			"Invalid import ID",
+			fmt.Sprintf("Expected the type and number of the rule separated by a slash, such as source/100, got %q.", req.ID),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("type"), ruleType)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("rule"), number)...)
+}
+
+func (m *NatRuleResourceModel) path(version vyos.Version) []string {
+	return natRulePath(m.Type.ValueString(), m.Rule.ValueInt64())
+}
+
+// config returns the configuration tree of the rule in the layout of
+// version.
+func (m *NatRuleResourceModel) config(version vyos.Version) map[string]any {
+	ruleType := m.Type.ValueString()
+
+	tree := map[string]any{}
+	setTypedString(tree, "description", m.Description)
+	setTypedString(tree, "protocol", m.Protocol)
+	for attribute, value := range map[string]types.String{"inbound_interface": m.InboundInterface, "outbound_interface": m.OutboundInterface} {
+		if value.IsNull() || value.IsUnknown() {
+			continue
+		}
+		if natInterfaceNames(version, ruleType) {
+			tree[typedNodeName(attribute)] = map[string]any{"name": value.ValueString()}
+		} else {
+			tree[typedNodeName(attribute)] = value.ValueString()
+		}
+	}
+
+	matchAddress := natMatchAddressNode(ruleType)
+	if match := m.Source.config(matchAddress); len(match) > 0 {
+		tree["source"] = match
+	}
+	if match := m.Destination.config(matchAddress); len(match) > 0 {
+		tree["destination"] = match
+	}
+	if translation := m.Translation.config("address"); len(translation) > 0 {
+		tree["translation"] = translation
+	}
+
+	if m.Exclude.ValueBool() {
+		tree["exclude"] = map[string]any{}
+	}
+	if m.Log.ValueBool() {
+		tree["log"] = ruleLogConfig(version)
+	}
+	return tree
+}
+
+func (m *NatRuleAddress) config(addressNode string) map[string]any {
+	tree := map[string]any{}
+	if m == nil {
+		return tree
+	}
+	if !m.Address.IsNull() && !m.Address.IsUnknown() {
+		tree[addressNode] = m.Address.ValueString()
+	}
+	setTypedString(tree, "port", m.Port)
+	return tree
+}
+
+// read sets the model to the rule configuration config, in the layout of
+// any VyOS version.
+func (m *NatRuleResourceModel) read(config any) {
+	m.Description = typedString(config, "description")
+	m.Protocol = typedString(config, "protocol")
+	m.InboundInterface = readNatInterface(typedChild(config, "inbound_interface"))
+	m.OutboundInterface = readNatInterface(typedChild(config, "outbound_interface"))
+
+	matchAddress := natMatchAddressNode(m.Type.ValueString())
+	m.Source = readNatRuleAddress(typedChild(config, "source"), matchAddress)
+	m.Destination = readNatRuleAddress(typedChild(config, "destination"), matchAddress)
+	m.Translation = readNatRuleAddress(typedChild(config, "translation"), "address")
+
+	m.Exclude = types.BoolValue(typedChild(config, "exclude") != nil)
+	m.Log = readRuleLog(config)
+}
+
+func readNatRuleAddress(config any, addressNode string) *NatRuleAddress {
+	if config == nil {
+		return nil
+	}
+	return &NatRuleAddress{
+		Address: typedString(config, addressNode),
+		Port:    typedString(config, "port"),
+	}
+}
+
+// readNatInterface reads an interface of a rule, either a plain leaf or the
+// `name` beneath it.
+func readNatInterface(config any) types.String {
+	if tree, ok := config.(map[string]any); ok {
+		return typedString(tree, "name")
+	}
+	if name, ok := config.(string); ok {
+		return types.StringValue(name)
+	}
+	return types.StringNull()
+}
+
+// natInterfaceNames reports whether rules of ruleType set their interfaces
+// as `inbound-interface name`, which static rules never do.
+func natInterfaceNames(version vyos.Version, ruleType string) bool {
+	return ruleType != "static" && version.Supports(vyos.FeatureNatInterfaceNames)
+}
+
+// natMatchAddressNode returns the node the source and destination addresses
+// of rules of ruleType are set on, `prefix` for NPTv6 source rules.
+func natMatchAddressNode(ruleType string) string {
+	if ruleType == "nat66-source" {
+		return "prefix"
+	}
+	return "address"
+}
+
+func isNatRuleType(ruleType string) bool {
+	for _, known := range natRuleTypes {
+		if ruleType == known {
+			return true
+		}
+	}
+	return false
+}
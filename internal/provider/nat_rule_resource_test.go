@@ -0,0 +1,171 @@
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccNatRuleResource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: testAccNatRuleResourceConfig("source", 100, "nat source rule 100", `
+  description        = "Masquerade the LAN"
+  outbound_interface = "eth0"
+  source = {
+    address = "192.0.2.0/24"
+  }
+  translation = {
+    address = "masquerade"
+  }
+`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("vyos_nat_rule.test", "id", "nat source rule 100"),
+					resource.TestCheckResourceAttr("vyos_nat_rule.test", "log", "false"),
+					resource.TestCheckResourceAttr("data.vyos_config.test", "value", `{"description":"Masquerade the LAN","outbound-interface":{"name":"eth0"},`+
+						`"source":{"address":"192.0.2.0/24"},"translation":{"address":"masquerade"}}`),
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:            "vyos_nat_rule.test",
+				ImportState:             true,
+				ImportStateId:           "source/100",
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{},
+			},
+			// Update and Read testing
+			{
+				Config: testAccNatRuleResourceConfig("source", 100, "nat source rule 100", `
+  outbound_interface = "eth0"
+  protocol           = "tcp"
+  source = {
+    address = "192.0.2.0/24"
+    port    = "1024-65535"
+  }
+  translation = {
+    address = "203.0.113.1"
+  }
+  log = true
+`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckNoResourceAttr("vyos_nat_rule.test", "description"),
+					resource.TestCheckResourceAttr("data.vyos_config.test", "value", `{"log":{},"outbound-interface":{"name":"eth0"},"protocol":"tcp",`+
+						`"source":{"address":"192.0.2.0/24","port":"1024-65535"},"translation":{"address":"203.0.113.1"}}`),
+				),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func TestAccNatRuleResourceNat66(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: testAccNatRuleResourceConfig("nat66-source", 10, "nat66 source rule 10", `
+  outbound_interface = "eth0"
+  source = {
+    address = "fc00::/64"
+  }
+  translation = {
+    address = "2001:db8::/64"
+  }
+`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("vyos_nat_rule.test", "id", "nat66 source rule 10"),
+					resource.TestCheckResourceAttr("vyos_nat_rule.test", "source.address", "fc00::/64"),
+					resource.TestCheckResourceAttr("data.vyos_config.test", "value", `{"outbound-interface":{"name":"eth0"},"source":{"prefix":"fc00::/64"},"translation":{"address":"2001:db8::/64"}}`),
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:            "vyos_nat_rule.test",
+				ImportState:             true,
+				ImportStateId:           "nat66-source/10",
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{},
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func TestAccNatRuleResourceVyos13(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			server := testAccPreCheck(t)
+			if server == nil {
+				t.Skip("older VyOS versions are only tested against the fake server")
+			}
+			server.SetVersion("1.3.8")
+		},
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: testAccNatRuleResourceConfig("destination", 20, "nat destination rule 20", `
+  inbound_interface = "eth0"
+  protocol          = "tcp"
+  destination = {
+    port = "443"
+  }
+  translation = {
+    address = "192.0.2.10"
+    port    = "8443"
+  }
+  log = true
+`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("vyos_nat_rule.test", "inbound_interface", "eth0"),
+					resource.TestCheckResourceAttr("vyos_nat_rule.test", "log", "true"),
+					resource.TestCheckResourceAttr("data.vyos_config.test", "value", `{"destination":{"port":"443"},"inbound-interface":"eth0","log":"enable",`+
+						`"protocol":"tcp","translation":{"address":"192.0.2.10","port":"8443"}}`),
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:            "vyos_nat_rule.test",
+				ImportState:             true,
+				ImportStateId:           "destination/20",
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{},
+			},
+			// Static NAT is not available on VyOS 1.3
+			{
+				Config: `
+resource "vyos_nat_rule" "static" {
+  type              = "static"
+  rule              = 10
+  inbound_interface = "eth0"
+}
+`,
+				ExpectError: regexp.MustCompile(`static\s+NAT\s+requires\s+VyOS\s+1.4\s+or\s+later`),
+			},
+		},
+	})
+}
+
+func testAccNatRuleResourceConfig(ruleType string, rule int, path string, attributes string) string {
+	return fmt.Sprintf(`
+resource "vyos_nat_rule" "test" {
+  type = %[1]q
+  rule = %[2]d
+%[4]s}
+
+data "vyos_config" "test" {
+  path = %[3]q
+
+  depends_on = [vyos_nat_rule.test]
+}
+`, ruleType, rule, path, attributes)
+}
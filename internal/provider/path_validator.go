@@ -0,0 +1,91 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/TGNThump/terraform-provider-vyos/internal/vyos"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+)
+
+var _ validator.String = pathValidator{}
+
+// pathValidator checks a configuration path can be split into components.
+type pathValidator struct {
+	allowEmpty bool
+}
+
+func (v pathValidator) Description(ctx context.Context) string {
+	return "value must be a space separated VyOS configuration path, quoting components which contain spaces"
+}
+
+func (v pathValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v pathValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	components, err := vyos.ParsePath(req.ConfigValue.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(req.Path, "Invalid configuration path", err.Error())
+	} else if len(components) == 0 && !v.allowEmpty {
+		resp.Diagnostics.AddAttributeError(req.Path, "Invalid configuration path", "The configuration path cannot be empty.")
+	}
+}
+
+var _ validator.String = setCommandValidator{}
+
+// setCommandValidator checks a VyOS CLI `set` command can be parsed.
+type setCommandValidator struct{}
+
+func (v setCommandValidator) Description(ctx context.Context) string {
+	return "value must be a VyOS `set` command, quoting components which contain spaces"
+}
+
+func (v setCommandValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v setCommandValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	if _, err := vyos.ParseSetCommand(req.ConfigValue.ValueString()); err != nil {
+		resp.Diagnostics.AddAttributeError(req.Path, "Invalid set command", err.Error())
+	}
+}
+
+var _ validator.String = constraintValidator{}
+
+// constraintValidator checks a value against a constraint of the VyOS
+// interface definitions.
+type constraintValidator struct {
+	constraint vyos.SchemaConstraint
+}
+
+func (v constraintValidator) Description(ctx context.Context) string {
+	if v.constraint.Error != "" {
+		return v.constraint.Error
+	}
+	return "value must be accepted by VyOS"
+}
+
+func (v constraintValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v constraintValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	if !v.constraint.Allows(req.ConfigValue.ValueString()) {
+		resp.Diagnostics.AddAttributeError(req.Path, "Invalid configuration value",
+			fmt.Sprintf("%q is not valid: %s.", req.ConfigValue.ValueString(), strings.TrimSuffix(v.Description(ctx), ".")))
+	}
+}
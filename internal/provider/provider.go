@@ -2,20 +2,25 @@ package provider
 
 import (
 	"context"
-	"crypto/tls"
-	"net/http"
+	"fmt"
+	"net/url"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
+	"github.com/TGNThump/terraform-provider-vyos/internal/transport"
 	"github.com/TGNThump/terraform-provider-vyos/internal/vyos"
-	"github.com/foltik/vyos-client-go/client"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"golang.org/x/crypto/ssh"
 )
 
 // Ensure VyOSProvider satisfies various provider interfaces.
@@ -35,6 +40,31 @@ type VyOSProviderModel struct {
 	ApiKey     types.String `tfsdk:"api_key"`
 	SkipSaving types.Bool   `tfsdk:"skip_saving"`
 	SaveFile   types.String `tfsdk:"save_file"`
+
+	CommitConfirmMinutes types.Int64 `tfsdk:"commit_confirm_minutes"`
+
+	MaxRetries   types.Int64  `tfsdk:"max_retries"`
+	RetryMaxWait types.String `tfsdk:"retry_max_wait"`
+
+	Insecure      types.Bool   `tfsdk:"insecure"`
+	CaCertFile    types.String `tfsdk:"ca_cert_file"`
+	CaCertPem     types.String `tfsdk:"ca_cert_pem"`
+	ClientCert    types.String `tfsdk:"client_cert"`
+	ClientKey     types.String `tfsdk:"client_key"`
+	TlsServerName types.String `tfsdk:"tls_server_name"`
+
+	ConnectTimeout types.String `tfsdk:"connect_timeout"`
+	RequestTimeout types.String `tfsdk:"request_timeout"`
+	ProxyUrl       types.String `tfsdk:"proxy_url"`
+
+	Transport     types.String `tfsdk:"transport"`
+	SshHost       types.String `tfsdk:"ssh_host"`
+	SshUser       types.String `tfsdk:"ssh_user"`
+	SshPassword   types.String `tfsdk:"ssh_password"`
+	SshPrivateKey types.String `tfsdk:"ssh_private_key"`
+	SshHostKey    types.String `tfsdk:"ssh_host_key"`
+
+	SshInsecureIgnoreHostKey types.Bool `tfsdk:"ssh_insecure_ignore_host_key"`
 }
 
 func (p *VyOSProvider) Metadata(ctx context.Context, req provider.MetadataRequest, resp *provider.MetadataResponse) {
@@ -62,6 +92,101 @@ func (p *VyOSProvider) Schema(ctx context.Context, request provider.SchemaReques
 				Optional:            true,
 				MarkdownDescription: "Remote file path to save the config too.",
 			},
+			"commit_confirm_minutes": schema.Int64Attribute{
+				Optional: true,
+				MarkdownDescription: "Commit changes with `commit-confirm`, so the router rolls them back after this many minutes " +
+					"unless the API still answers after the commit. Requires VyOS 1.4 or later and the `http` transport. " +
+					"Disabled when unset or 0.",
+			},
+			"max_retries": schema.Int64Attribute{
+				Optional: true,
+				MarkdownDescription: "How many times a request is retried when the configuration is locked by another session " +
+					"or the router cannot be reached. Changes are only retried when the router cannot have applied them. " +
+					"Defaults to 5, 0 disables retrying.",
+			},
+			"retry_max_wait": schema.StringAttribute{
+				Optional: true,
+				MarkdownDescription: "Longest wait between two attempts of a request, such as `30s`. The wait starts at half a " +
+					"second and doubles with every attempt. Defaults to `30s`.",
+			},
+			"insecure": schema.BoolAttribute{
+				Optional: true,
+				MarkdownDescription: "Set to true to skip verifying the TLS certificate of the router. The API key can then be " +
+					"intercepted, prefer `ca_cert_file` for self-signed certificates. Defaults to false.",
+			},
+			"ca_cert_file": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "Path to a PEM file of CA certificates to verify the router with, instead of the system CAs.",
+			},
+			"ca_cert_pem": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "PEM encoded CA certificates to verify the router with, instead of the system CAs.",
+			},
+			"client_cert": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "PEM encoded client certificate, or the path to one, for mutual TLS.",
+			},
+			"client_key": schema.StringAttribute{
+				Optional:            true,
+				Sensitive:           true,
+				MarkdownDescription: "PEM encoded private key of `client_cert`, or the path to one.",
+			},
+			"tls_server_name": schema.StringAttribute{
+				Optional: true,
+				MarkdownDescription: "Name to verify the certificate of the router against, for routers reached by IP address " +
+					"or through a name not in their certificate.",
+			},
+			"connect_timeout": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "How long connecting to the router may take, such as `30s`. Defaults to `30s`.",
+			},
+			"request_timeout": schema.StringAttribute{
+				Optional: true,
+				MarkdownDescription: "How long a single API request may take, including the commit it triggers, such as `5m`. " +
+					"Defaults to `10m`.",
+			},
+			"proxy_url": schema.StringAttribute{
+				Optional: true,
+				MarkdownDescription: "URL of an `http`, `https` or `socks5` proxy to reach the router through, such as " +
+					"`socks5://bastion:1080`. Defaults to the proxy set by the `HTTPS_PROXY` environment variable.",
+			},
+			"transport": schema.StringAttribute{
+				Optional: true,
+				MarkdownDescription: "How to talk to the router. `http` uses the VyOS HTTP API at `endpoint`. `ssh` drives the " +
+					"VyOS CLI over SSH at `ssh_host`, for routers without `service https api`. The `ssh` transport requires " +
+					"VyOS 1.4 or later and cannot be combined with `commit_confirm_minutes`. Defaults to `http`.",
+				Validators: []validator.String{
+					stringvalidator.OneOf(transportHttp, transportSsh),
+				},
+			},
+			"ssh_host": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "Host of the router for the `ssh` transport, with an optional port such as `vyos:2222`.",
+			},
+			"ssh_user": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "User to log in as for the `ssh` transport.",
+			},
+			"ssh_password": schema.StringAttribute{
+				Optional:            true,
+				Sensitive:           true,
+				MarkdownDescription: "Password to log in with for the `ssh` transport.",
+			},
+			"ssh_private_key": schema.StringAttribute{
+				Optional:            true,
+				Sensitive:           true,
+				MarkdownDescription: "PEM encoded private key, or the path to one, to log in with for the `ssh` transport.",
+			},
+			"ssh_host_key": schema.StringAttribute{
+				Optional: true,
+				MarkdownDescription: "Public host key of the router in `authorized_keys` format, such as `ssh-ed25519 AAAA...`, " +
+					"to verify it with for the `ssh` transport. Required unless `ssh_insecure_ignore_host_key` is set.",
+			},
+			"ssh_insecure_ignore_host_key": schema.BoolAttribute{
+				Optional: true,
+				MarkdownDescription: "Set to true to accept any host key for the `ssh` transport. The connection can then be " +
+					"intercepted, prefer `ssh_host_key`. `insecure` only skips verifying TLS certificates. Defaults to false.",
+			},
 		},
 	}
 }
@@ -115,6 +240,85 @@ func (p *VyOSProvider) Configure(ctx context.Context, req provider.ConfigureRequ
 		}
 	}
 
+	commit_confirm_minutes := int64(0)
+	commit_confirm_minutes_str := os.Getenv("VYOS_COMMIT_CONFIRM_MINUTES")
+	if commit_confirm_minutes_str != "" {
+		var err error = nil
+		commit_confirm_minutes, err = strconv.ParseInt(commit_confirm_minutes_str, 10, 64)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("commit_confirm_minutes"),
+				"Unable to parse VYOS_COMMIT_CONFIRM_MINUTES",
+				"The provider cannot create the VyOS API client as there is an invalid value for the VYOS_COMMIT_CONFIRM_MINUTES environment value.",
+			)
+		}
+	}
+
+	max_retries := int64(vyos.DefaultMaxRetries)
+	max_retries_str := os.Getenv("VYOS_MAX_RETRIES")
+	if max_retries_str != "" {
+		var err error = nil
+		max_retries, err = strconv.ParseInt(max_retries_str, 10, 64)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("max_retries"),
+				"Unable to parse VYOS_MAX_RETRIES",
+				"The provider cannot create the VyOS API client as there is an invalid value for the VYOS_MAX_RETRIES environment value.",
+			)
+		}
+	}
+
+	retry_max_wait_str := os.Getenv("VYOS_RETRY_MAX_WAIT")
+
+	insecure := false
+	insecure_str := os.Getenv("VYOS_INSECURE")
+	if insecure_str != "" {
+		var err error = nil
+		insecure, err = strconv.ParseBool(insecure_str)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("insecure"),
+				"Unable to parse VYOS_INSECURE",
+				"The provider cannot create the VyOS API client as there is an invalid value for the VYOS_INSECURE environment value.",
+			)
+		}
+	}
+
+	tls_options := transport.TlsOptions{
+		Insecure:   insecure,
+		CaCertFile: os.Getenv("VYOS_CA_CERT_FILE"),
+		CaCertPem:  os.Getenv("VYOS_CA_CERT_PEM"),
+		ClientCert: os.Getenv("VYOS_CLIENT_CERT"),
+		ClientKey:  os.Getenv("VYOS_CLIENT_KEY"),
+		ServerName: os.Getenv("VYOS_TLS_SERVER_NAME"),
+	}
+
+	transport_name := os.Getenv("VYOS_TRANSPORT")
+	ssh_options := sshOptions{
+		host:       os.Getenv("VYOS_SSH_HOST"),
+		user:       os.Getenv("VYOS_SSH_USER"),
+		password:   os.Getenv("VYOS_SSH_PASSWORD"),
+		privateKey: os.Getenv("VYOS_SSH_PRIVATE_KEY"),
+		hostKey:    os.Getenv("VYOS_SSH_HOST_KEY"),
+	}
+
+	ssh_insecure_str := os.Getenv("VYOS_SSH_INSECURE_IGNORE_HOST_KEY")
+	if ssh_insecure_str != "" {
+		var err error = nil
+		ssh_options.insecureIgnoreHostKey, err = strconv.ParseBool(ssh_insecure_str)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("ssh_insecure_ignore_host_key"),
+				"Unable to parse VYOS_SSH_INSECURE_IGNORE_HOST_KEY",
+				"The provider cannot log in to the router as there is an invalid value for the VYOS_SSH_INSECURE_IGNORE_HOST_KEY environment value.",
+			)
+		}
+	}
+
+	connect_timeout_str := os.Getenv("VYOS_CONNECT_TIMEOUT")
+	request_timeout_str := os.Getenv("VYOS_REQUEST_TIMEOUT")
+	proxy_url_str := os.Getenv("VYOS_PROXY_URL")
+
 	if !config.Endpoint.IsNull() {
 		endpoint = config.Endpoint.ValueString()
 	}
@@ -131,7 +335,160 @@ func (p *VyOSProvider) Configure(ctx context.Context, req provider.ConfigureRequ
 		skip_saving = config.SkipSaving.ValueBool()
 	}
 
-	if endpoint == "" {
+	if !config.CommitConfirmMinutes.IsNull() {
+		commit_confirm_minutes = config.CommitConfirmMinutes.ValueInt64()
+	}
+
+	if !config.MaxRetries.IsNull() {
+		max_retries = config.MaxRetries.ValueInt64()
+	}
+
+	if !config.RetryMaxWait.IsNull() {
+		retry_max_wait_str = config.RetryMaxWait.ValueString()
+	}
+
+	if !config.Insecure.IsNull() {
+		tls_options.Insecure = config.Insecure.ValueBool()
+	}
+
+	if !config.CaCertFile.IsNull() {
+		tls_options.CaCertFile = config.CaCertFile.ValueString()
+	}
+
+	if !config.CaCertPem.IsNull() {
+		tls_options.CaCertPem = config.CaCertPem.ValueString()
+	}
+
+	if !config.ClientCert.IsNull() {
+		tls_options.ClientCert = config.ClientCert.ValueString()
+	}
+
+	if !config.ClientKey.IsNull() {
+		tls_options.ClientKey = config.ClientKey.ValueString()
+	}
+
+	if !config.TlsServerName.IsNull() {
+		tls_options.ServerName = config.TlsServerName.ValueString()
+	}
+
+	if !config.ConnectTimeout.IsNull() {
+		connect_timeout_str = config.ConnectTimeout.ValueString()
+	}
+
+	if !config.RequestTimeout.IsNull() {
+		request_timeout_str = config.RequestTimeout.ValueString()
+	}
+
+	if !config.ProxyUrl.IsNull() {
+		proxy_url_str = config.ProxyUrl.ValueString()
+	}
+
+	if !config.Transport.IsNull() {
+		transport_name = config.Transport.ValueString()
+	}
+
+	if !config.SshHost.IsNull() {
+		ssh_options.host = config.SshHost.ValueString()
+	}
+
+	if !config.SshUser.IsNull() {
+		ssh_options.user = config.SshUser.ValueString()
+	}
+
+	if !config.SshPassword.IsNull() {
+		ssh_options.password = config.SshPassword.ValueString()
+	}
+
+	if !config.SshPrivateKey.IsNull() {
+		ssh_options.privateKey = config.SshPrivateKey.ValueString()
+	}
+
+	if !config.SshHostKey.IsNull() {
+		ssh_options.hostKey = config.SshHostKey.ValueString()
+	}
+
+	if !config.SshInsecureIgnoreHostKey.IsNull() {
+		ssh_options.insecureIgnoreHostKey = config.SshInsecureIgnoreHostKey.ValueBool()
+	}
+
+	if transport_name == "" {
+		transport_name = transportHttp
+	}
+
+	if transport_name != transportHttp && transport_name != transportSsh {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("transport"),
+			"Invalid transport",
+			"The transport set in the configuration or the VYOS_TRANSPORT environment variable must be http or ssh.",
+		)
+	}
+
+	tls_config, err := tls_options.TlsConfig()
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Invalid TLS configuration",
+			"The provider cannot create the VyOS API client as the TLS settings are invalid: "+err.Error(),
+		)
+	}
+
+	if max_retries < 0 {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("max_retries"),
+			"Invalid max retries",
+			"The number of times a request is retried cannot be negative.",
+		)
+	}
+
+	retry_max_wait := parseDuration(&resp.Diagnostics, "retry_max_wait", "VYOS_RETRY_MAX_WAIT", retry_max_wait_str, vyos.DefaultRetryMaxWait)
+	connect_timeout := parseDuration(&resp.Diagnostics, "connect_timeout", "VYOS_CONNECT_TIMEOUT", connect_timeout_str, transport.DefaultConnectTimeout)
+	request_timeout := parseDuration(&resp.Diagnostics, "request_timeout", "VYOS_REQUEST_TIMEOUT", request_timeout_str, transport.DefaultRequestTimeout)
+
+	var proxy_url *url.URL
+	if proxy_url_str != "" {
+		var err error = nil
+		proxy_url, err = transport.ParseProxyUrl(proxy_url_str)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("proxy_url"),
+				"Invalid proxy URL",
+				"The provider cannot create the VyOS API client as the proxy URL set in the configuration or the "+
+					"VYOS_PROXY_URL environment variable is invalid: "+err.Error(),
+			)
+		}
+	}
+
+	if commit_confirm_minutes < 0 {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("commit_confirm_minutes"),
+			"Invalid commit confirm minutes",
+			"The number of minutes before an unconfirmed commit is rolled back cannot be negative.",
+		)
+	}
+
+	if commit_confirm_minutes > 0 && transport_name == transportSsh {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("commit_confirm_minutes"),
+			"Commit confirm not supported over SSH",
+			"Changes can only be committed with commit-confirm through the VyOS HTTP API. "+
+				"Unset commit_confirm_minutes or use the http transport.",
+		)
+	}
+
+	var ssh_config *ssh.ClientConfig
+	if transport_name == transportSsh {
+		ssh_options.timeout = connect_timeout
+
+		var err error = nil
+		ssh_config, err = ssh_options.clientConfig()
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Invalid SSH configuration",
+				"The provider cannot log in to the router as the SSH settings are invalid: "+err.Error(),
+			)
+		}
+	}
+
+	if endpoint == "" && transport_name == transportHttp {
 		resp.Diagnostics.AddAttributeError(
 			path.Root("endpoint"),
 			"Missing VyOS API Endpoint",
@@ -141,7 +498,7 @@ func (p *VyOSProvider) Configure(ctx context.Context, req provider.ConfigureRequ
 		)
 	}
 
-	if api_key == "" {
+	if api_key == "" && transport_name == transportHttp {
 		resp.Diagnostics.AddAttributeError(
 			path.Root("api_key"),
 			"Missing VyOS API key",
@@ -155,25 +512,46 @@ func (p *VyOSProvider) Configure(ctx context.Context, req provider.ConfigureRequ
 		return
 	}
 
-	transport := http.DefaultTransport.(*http.Transport).Clone()
-	transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
-	httpClient := &http.Client{Transport: transport, Timeout: 10 * time.Minute}
+	var backend vyos.Backend
+	if transport_name == transportSsh {
+		backend = vyos.NewSSHBackend(ssh_options.address(), ssh_config)
+	} else {
+		httpClient := transport.NewHttpClient(transport.HttpClientOptions{
+			TlsConfig:      tls_config,
+			ConnectTimeout: connect_timeout,
+			RequestTimeout: request_timeout,
+			ProxyUrl:       proxy_url,
+		})
 
-	apiClient := client.NewWithClient(httpClient, endpoint, api_key)
-	vyosConfig := vyos.New(apiClient, skip_saving, save_file)
+		backend = vyos.NewApiBackend(httpClient, endpoint, api_key)
+	}
+
+	vyosConfig := vyos.New(backend, skip_saving, save_file,
+		vyos.WithCommitConfirm(commit_confirm_minutes),
+		vyos.WithRetry(max_retries, retry_max_wait),
+	)
 
 	resp.DataSourceData = vyosConfig
 	resp.ResourceData = vyosConfig
 }
 
 func (p *VyOSProvider) Resources(ctx context.Context) []func() resource.Resource {
-	return []func() resource.Resource{
+	return append([]func() resource.Resource{
 		NewConfigResource,
-	}
+		NewConfigCommandsResource,
+		NewFirewallGroupResource,
+		NewFirewallGroupMemberResource,
+		NewFirewallRulesetResource,
+		NewNatRuleResource,
+	}, generatedResources...)
 }
 
 func (p *VyOSProvider) DataSources(ctx context.Context) []func() datasource.DataSource {
-	return []func() datasource.DataSource{}
+	return []func() datasource.DataSource{
+		NewConfigDataSource,
+		NewShowDataSource,
+		NewVersionDataSource,
+	}
 }
 
 func New(version string) func() provider.Provider {
@@ -183,3 +561,21 @@ func New(version string) func() provider.Provider {
 		}
 	}
 }
+
+// parseDuration parses the duration set for attribute, or in the environment
+// variable env, returning fallback when neither is set.
+func parseDuration(diags *diag.Diagnostics, attribute string, env string, value string, fallback time.Duration) time.Duration {
+	if value == "" {
+		return fallback
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil || duration < 0 {
+		diags.AddAttributeError(
+			path.Root(attribute),
+			"Invalid "+strings.ReplaceAll(attribute, "_", " "),
+			fmt.Sprintf("The value must be a positive duration such as 30s, set in the configuration or the %s environment variable.", env),
+		)
+	}
+	return duration
+}
@@ -1,8 +1,10 @@
 package provider
 
 import (
+	"os"
 	"testing"
 
+	"github.com/TGNThump/terraform-provider-vyos/internal/vyostest"
 	"github.com/hashicorp/terraform-plugin-framework/providerserver"
 	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
 )
@@ -15,8 +17,17 @@ var testAccProtoV6ProviderFactories = map[string]func() (tfprotov6.ProviderServe
 	"vyos": providerserver.NewProtocol6WithError(New("test")()),
 }
 
-func testAccPreCheck(t *testing.T) {
-	// You can add code here to run prior to any test case execution, for example assertions
-	// about the appropriate environment variables being set are common to see in a pre-check
-	// function.
+// testAccPreCheck returns the fake API server the tests run against, or nil
+// when testing against a router.
+func testAccPreCheck(t *testing.T) *vyostest.Server {
+	// Without a router to test against, fall back to the in-memory fake API.
+	if os.Getenv("VYOS_ENDPOINT") == "" {
+		server := vyostest.NewServer("test")
+		t.Cleanup(server.Close)
+
+		t.Setenv("VYOS_ENDPOINT", server.URL)
+		t.Setenv("VYOS_API_KEY", server.Key)
+		return server
+	}
+	return nil
 }
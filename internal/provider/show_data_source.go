@@ -0,0 +1,232 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/TGNThump/terraform-provider-vyos/internal/vyos"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces
+var _ datasource.DataSource = &ShowDataSource{}
+var _ datasource.DataSourceWithConfigure = &ShowDataSource{}
+
+func NewShowDataSource() datasource.DataSource {
+	return &ShowDataSource{}
+}
+
+// ShowDataSource defines the data source implementation.
+type ShowDataSource struct {
+	vyosConfig *vyos.VyosConfig
+}
+
+// ShowDataSourceModel describes the data source data model.
+type ShowDataSourceModel struct {
+	Command    types.String `tfsdk:"command"`
+	Output     types.String `tfsdk:"output"`
+	Interfaces types.List   `tfsdk:"interfaces"`
+	Version    types.Object `tfsdk:"version"`
+	Id         types.String `tfsdk:"id"`
+}
+
+type showInterfaceModel struct {
+	Name        types.String `tfsdk:"name"`
+	Addresses   []string     `tfsdk:"addresses"`
+	State       types.String `tfsdk:"state"`
+	Link        types.String `tfsdk:"link"`
+	Description types.String `tfsdk:"description"`
+}
+
+var showInterfaceAttrTypes = map[string]attr.Type{
+	"name":        types.StringType,
+	"addresses":   types.ListType{ElemType: types.StringType},
+	"state":       types.StringType,
+	"link":        types.StringType,
+	"description": types.StringType,
+}
+
+// showVersionAttributes are the `show version` labels exposed, in snake case.
+var showVersionAttributes = []string{
+	"version",
+	"release_train",
+	"built_by",
+	"built_on",
+	"architecture",
+	"system_type",
+	"hardware_vendor",
+	"hardware_model",
+}
+
+func (d *ShowDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_show"
+}
+
+func (d *ShowDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	versionAttributes := map[string]schema.Attribute{}
+	for _, name := range showVersionAttributes {
+		versionAttributes[name] = schema.StringAttribute{
+			MarkdownDescription: fmt.Sprintf("The `%s` line of `show version`.", strings.ReplaceAll(name, "_", " ")),
+			Computed:            true,
+		}
+	}
+
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Runs an operational mode `show` command on the router.",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Command identifier",
+			},
+			"command": schema.StringAttribute{
+				MarkdownDescription: "Command to run, such as `show interfaces` or `show version`. The leading `show` is optional.",
+				Required:            true,
+			},
+			"output": schema.StringAttribute{
+				MarkdownDescription: "Raw output of the command.",
+				Computed:            true,
+			},
+			"interfaces": schema.ListNestedAttribute{
+				MarkdownDescription: "Interfaces parsed from the output of `show interfaces`, null for other commands.",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"name": schema.StringAttribute{
+							MarkdownDescription: "Interface name.",
+							Computed:            true,
+						},
+						"addresses": schema.ListAttribute{
+							MarkdownDescription: "IP addresses assigned to the interface, including DHCP assigned addresses.",
+							ElementType:         types.StringType,
+							Computed:            true,
+						},
+						"state": schema.StringAttribute{
+							MarkdownDescription: "Administrative state, `u` for up, `D` for down or `A` for admin down.",
+							Computed:            true,
+						},
+						"link": schema.StringAttribute{
+							MarkdownDescription: "Link state, `u` for up or `D` for down.",
+							Computed:            true,
+						},
+						"description": schema.StringAttribute{
+							MarkdownDescription: "Interface description.",
+							Computed:            true,
+						},
+					},
+				},
+			},
+			"version": schema.SingleNestedAttribute{
+				MarkdownDescription: "Version information parsed from the output of `show version`, null for other commands.",
+				Computed:            true,
+				Attributes:          versionAttributes,
+			},
+		},
+	}
+}
+
+func (d *ShowDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	vyosConfig, ok := req.ProviderData.(*vyos.VyosConfig)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *vyos.VyosConfig, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.vyosConfig = vyosConfig
+}
+
+func (d *ShowDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data ShowDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	command, err := vyos.ParsePath(data.Command.ValueString())
+	if err == nil && len(command) > 0 && command[0] == "show" {
+		command = command[1:]
+	}
+	if err == nil && len(command) == 0 {
+		err = fmt.Errorf("no command given")
+	}
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("command"), "Invalid show command", err.Error())
+		return
+	}
+
+	tflog.Info(ctx, "Running show "+vyos.FormatPath(command))
+
+	output, err := d.vyosConfig.RunShow(ctx, command)
+	if err != nil {
+		addVyosError(&resp.Diagnostics, path.Root("command"), err)
+		return
+	}
+
+	data.Id = types.StringValue("show " + vyos.FormatPath(command))
+	data.Output = types.StringValue(output)
+	data.Interfaces = types.ListNull(types.ObjectType{AttrTypes: showInterfaceAttrTypes})
+	data.Version = types.ObjectNull(showVersionAttrTypes())
+
+	switch vyos.FormatPath(command) {
+	case "interfaces":
+		interfaces := []showInterfaceModel{}
+		for _, iface := range vyos.ParseShowInterfaces(output) {
+			interfaces = append(interfaces, showInterfaceModel{
+				Name:        types.StringValue(iface.Name),
+				Addresses:   iface.Addresses,
+				State:       types.StringValue(iface.State),
+				Link:        types.StringValue(iface.Link),
+				Description: types.StringValue(iface.Description),
+			})
+		}
+
+		value, diags := types.ListValueFrom(ctx, types.ObjectType{AttrTypes: showInterfaceAttrTypes}, interfaces)
+		resp.Diagnostics.Append(diags...)
+		data.Interfaces = value
+
+	case "version":
+		parsed := vyos.ParseShowVersion(output)
+		attributes := map[string]attr.Value{}
+		for _, name := range showVersionAttributes {
+			if value, ok := parsed[name]; ok {
+				attributes[name] = types.StringValue(value)
+			} else {
+				attributes[name] = types.StringNull()
+			}
+		}
+
+		value, diags := types.ObjectValue(showVersionAttrTypes(), attributes)
+		resp.Diagnostics.Append(diags...)
+		data.Version = value
+	}
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func showVersionAttrTypes() map[string]attr.Type {
+	attrTypes := map[string]attr.Type{}
+	for _, name := range showVersionAttributes {
+		attrTypes[name] = types.StringType
+	}
+	return attrTypes
+}
@@ -0,0 +1,51 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccShowDataSource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			if server := testAccPreCheck(t); server != nil {
+				server.SetShowOutput("version", "Version:          VyOS 1.4.0\nRelease train:    sagitta\n")
+				server.SetShowOutput("interfaces", ""+
+					"Codes: S - State, L - Link, u - Up, D - Down, A - Admin Down\n"+
+					"Interface        IP Address                        S/L  Description\n"+
+					"---------        ----------                        ---  -----------\n"+
+					"eth0             192.0.2.10/24                     u/u  WAN\n"+
+					"lo               127.0.0.1/8                       u/u\n"+
+					"                 ::1/128\n")
+			}
+		},
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Read testing
+			{
+				Config: testAccShowDataSourceConfig,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet("data.vyos_show.version", "output"),
+					resource.TestCheckResourceAttrSet("data.vyos_show.version", "version.version"),
+					resource.TestCheckNoResourceAttr("data.vyos_show.version", "interfaces"),
+					resource.TestCheckResourceAttrSet("data.vyos_show.interfaces", "interfaces.0.name"),
+					resource.TestCheckTypeSetElemNestedAttrs("data.vyos_show.interfaces", "interfaces.*", map[string]string{
+						"name":        "lo",
+						"addresses.0": "127.0.0.1/8",
+					}),
+				),
+			},
+		},
+	})
+}
+
+const testAccShowDataSourceConfig = `
+data "vyos_show" "version" {
+  command = "show version"
+}
+
+data "vyos_show" "interfaces" {
+  command = "interfaces"
+}
+`
@@ -0,0 +1,89 @@
+package provider
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/TGNThump/terraform-provider-vyos/internal/transport"
+	"golang.org/x/crypto/ssh"
+)
+
+const (
+	// transportHttp talks to the router through the VyOS HTTP API.
+	transportHttp = "http"
+	// transportSsh drives the VyOS CLI over SSH.
+	transportSsh = "ssh"
+)
+
+// sshOptions are the settings used to log in to the router over SSH.
+type sshOptions struct {
+	host       string
+	user       string
+	password   string
+	privateKey string
+	hostKey    string
+	// insecureIgnoreHostKey accepts any host key, separate from the TLS
+	// insecure setting so skipping a self-signed certificate does not also
+	// skip verifying SSH.
+	insecureIgnoreHostKey bool
+	timeout               time.Duration
+}
+
+// address returns the host:port to connect to, on port 22 unless the host
+// names another.
+func (o sshOptions) address() string {
+	if _, _, err := net.SplitHostPort(o.host); err == nil {
+		return o.host
+	}
+	return net.JoinHostPort(o.host, "22")
+}
+
+// clientConfig builds the configuration of the SSH client.
+func (o sshOptions) clientConfig() (*ssh.ClientConfig, error) {
+	if o.host == "" {
+		return nil, errors.New("ssh_host must be set to use the ssh transport")
+	}
+	if o.user == "" {
+		return nil, errors.New("ssh_user must be set to use the ssh transport")
+	}
+
+	config := &ssh.ClientConfig{
+		User:    o.user,
+		Timeout: o.timeout,
+	}
+
+	if o.privateKey != "" {
+		key, err := transport.PemOrFile(o.privateKey)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read SSH private key: %w", err)
+		}
+		signer, err := ssh.ParsePrivateKey(key)
+		if err != nil {
+			return nil, fmt.Errorf("invalid SSH private key: %w", err)
+		}
+		config.Auth = append(config.Auth, ssh.PublicKeys(signer))
+	}
+	if o.password != "" {
+		config.Auth = append(config.Auth, ssh.Password(o.password))
+	}
+	if len(config.Auth) == 0 {
+		return nil, errors.New("ssh_private_key or ssh_password must be set to use the ssh transport")
+	}
+
+	switch {
+	case o.hostKey != "":
+		hostKey, _, _, _, err := ssh.ParseAuthorizedKey([]byte(o.hostKey))
+		if err != nil {
+			return nil, fmt.Errorf("invalid SSH host key: %w", err)
+		}
+		config.HostKeyCallback = ssh.FixedHostKey(hostKey)
+	case o.insecureIgnoreHostKey:
+		config.HostKeyCallback = ssh.InsecureIgnoreHostKey()
+	default:
+		return nil, errors.New("ssh_host_key must be set to verify the router, or ssh_insecure_ignore_host_key to skip verification")
+	}
+
+	return config, nil
+}
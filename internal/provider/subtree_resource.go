@@ -0,0 +1,280 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/TGNThump/terraform-provider-vyos/internal/vyos"
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// subtreeModel is the data model of a hand-written resource managing the
+// configuration subtree at its path, such as a firewall ruleset. Besides its own
+// attributes the model has the `id` and `timeouts` ones.
+type subtreeModel interface {
+	// path returns the configuration path of the resource on version.
+	path(version vyos.Version) []string
+	// config returns the configuration tree of the resource in the layout
+	// of version.
+	config(version vyos.Version) map[string]any
+	// read sets the model to the configuration config, in the layout of any
+	// VyOS version.
+	read(config any)
+}
+
+// subtreeRequirer is implemented by models which not every VyOS version can
+// hold, such as interface groups.
+type subtreeRequirer interface {
+	// require reports when version cannot hold the resource.
+	require(version vyos.Version) diag.Diagnostics
+}
+
+// subtreeResource implements configuring, creating, reading, updating and
+// deleting a hand-written resource with model M, leaving its schema, import
+// and validation to the resource embedding it. Like typedResource, it
+// manages the subtree authoritatively and only changes the leaves which
+// differ from the router, so connections matching the rules or members which
+// did not change are not disrupted.
+type subtreeResource[M any, P interface {
+	*M
+	subtreeModel
+}] struct {
+	vyosConfig *vyos.VyosConfig
+	// kind names the configuration in log messages, such as `firewall
+	// ruleset`.
+	kind string
+	// keyAttribute is the attribute naming the configuration, where finding
+	// it already exists is reported.
+	keyAttribute string
+	// versioned is set when the path or layout of the configuration depends
+	// on the VyOS version, which then has to be known instead of assumed to
+	// be the latest.
+	versioned bool
+}
+
+func (r *subtreeResource[M, P]) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	vyosConfig, ok := req.ProviderData.(*vyos.VyosConfig)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *vyos.VyosConfig, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.vyosConfig = vyosConfig
+}
+
+// version returns the version of the router. When the resource is versioned
+// and the version cannot be detected, it reports an error rather than guess
+// the layout.
+func (r *subtreeResource[M, P]) version(ctx context.Context) (vyos.Version, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	if !r.versioned {
+		return r.vyosConfig.Version(ctx), diags
+	}
+
+	version, err := r.vyosConfig.DetectVersion(ctx)
+	if err != nil {
+		diags.AddError(
+			"Unable to detect the VyOS version",
+			fmt.Sprintf("The configuration path of the %s depends on the VyOS version, which could not be detected: %s", r.kind, err),
+		)
+	}
+	return version, diags
+}
+
+func (r *subtreeResource[M, P]) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	data := P(new(M))
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, data)...)
+
+	var timeout timeouts.Value
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("timeouts"), &timeout)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	createTimeout, diags := timeout.Create(ctx, defaultConfigTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, createTimeout)
+	defer cancel()
+
+	version, diags := r.version(ctx)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if requirer, ok := any(data).(subtreeRequirer); ok {
+		resp.Diagnostics.Append(requirer.require(version)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	components := data.path(version)
+
+	existing, err := r.vyosConfig.Show(ctx, components)
+	if err != nil {
+		addVyosError(&resp.Diagnostics, path.Root(r.keyAttribute), err)
+		return
+	}
+	if existing != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root(r.keyAttribute),
+			"Configuration already exists",
+			fmt.Sprintf("Configuration path '%s' already exists, try a resource import instead.", vyos.FormatPath(components)),
+		)
+		return
+	}
+
+	tflog.Info(ctx, "Setting "+r.kind+" "+vyos.FormatPath(components))
+
+	if err := r.vyosConfig.Apply(ctx, components, data.config(version)); err != nil {
+		addVyosError(&resp.Diagnostics, path.Root("id"), err)
+		return
+	}
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, data)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), vyos.FormatPath(components))...)
+}
+
+func (r *subtreeResource[M, P]) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	data := P(new(M))
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, data)...)
+
+	var timeout timeouts.Value
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("timeouts"), &timeout)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	readTimeout, diags := timeout.Read(ctx, defaultConfigTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, readTimeout)
+	defer cancel()
+
+	version, diags := r.version(ctx)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	components := data.path(version)
+	tflog.Info(ctx, "Reading "+r.kind+" "+vyos.FormatPath(components))
+
+	config, err := r.vyosConfig.Show(ctx, components)
+	if err != nil {
+		addVyosError(&resp.Diagnostics, path.Root("id"), err)
+		return
+	}
+	if config == nil {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	data.read(config)
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, data)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), vyos.FormatPath(components))...)
+}
+
+func (r *subtreeResource[M, P]) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	data := P(new(M))
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, data)...)
+
+	var timeout timeouts.Value
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("timeouts"), &timeout)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	updateTimeout, diags := timeout.Update(ctx, defaultConfigTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, updateTimeout)
+	defer cancel()
+
+	version, diags := r.version(ctx)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	components := data.path(version)
+	tflog.Info(ctx, "Updating "+r.kind+" "+vyos.FormatPath(components))
+
+	if err := r.vyosConfig.Apply(ctx, components, data.config(version)); err != nil {
+		addVyosError(&resp.Diagnostics, path.Root("id"), err)
+		return
+	}
+
+	// Save updated plan into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, data)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), vyos.FormatPath(components))...)
+}
+
+func (r *subtreeResource[M, P]) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	data := P(new(M))
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, data)...)
+
+	var timeout timeouts.Value
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("timeouts"), &timeout)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	deleteTimeout, diags := timeout.Delete(ctx, defaultConfigTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, deleteTimeout)
+	defer cancel()
+
+	version, diags := r.version(ctx)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	components := data.path(version)
+	tflog.Info(ctx, "Deleting "+r.kind+" "+vyos.FormatPath(components))
+
+	if err := r.vyosConfig.Delete(ctx, components); err != nil {
+		addVyosError(&resp.Diagnostics, path.Root("id"), err)
+		return
+	}
+}
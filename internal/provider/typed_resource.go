@@ -0,0 +1,513 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/TGNThump/terraform-provider-vyos/internal/vyos"
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces
+var _ resource.Resource = &typedResource{}
+var _ resource.ResourceWithImportState = &typedResource{}
+var _ resource.ResourceWithConfigure = &typedResource{}
+
+// typedResourceDefinition describes a resource generated from the VyOS
+// interface definitions by tools/resourcegen. Its attributes are named after
+// the nodes they set, with hyphens turned into underscores:
+//
+//   - string and number attributes set leaves
+//   - bool attributes set valueless nodes, such as `disable`
+//   - sets of strings set leaves holding several values
+//   - nested attributes set nodes, and maps of nested attributes tag nodes
+type typedResourceDefinition struct {
+	// TypeName is the resource type without the provider prefix, such as
+	// `service_ssh`.
+	TypeName    string
+	Description string
+	// Path is the configuration path of the resource, or of the tag node
+	// holding it when Tag is set.
+	Path []string
+	// Tag is set on resources managing a tag of the node at Path, such as
+	// an interface, whose value is the name attribute.
+	Tag           bool
+	TagValidators []validator.String
+	Attributes    map[string]schema.Attribute
+}
+
+// typedResource manages the subtree of a typedResourceDefinition
+// authoritatively, changing only the leaves which differ from the router.
+type typedResource struct {
+	definition typedResourceDefinition
+	vyosConfig *vyos.VyosConfig
+}
+
+// typedResourceAttributes are the attributes of a typed resource which do
+// not set configuration.
+var typedResourceAttributes = map[string]bool{"id": true, "name": true, "timeouts": true}
+
+func (r *typedResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_" + r.definition.TypeName
+}
+
+func (r *typedResource) Schema(ctx context.Context, request resource.SchemaRequest, response *resource.SchemaResponse) {
+	attributes := map[string]schema.Attribute{
+		"id": schema.StringAttribute{
+			Computed:            true,
+			MarkdownDescription: "Configuration path of the resource",
+			PlanModifiers: []planmodifier.String{
+				stringplanmodifier.UseStateForUnknown(),
+			},
+		},
+	}
+	if r.definition.Tag {
+		attributes["name"] = schema.StringAttribute{
+			MarkdownDescription: fmt.Sprintf("Name of the `%s` entry", vyos.FormatPath(r.definition.Path)),
+			Required:            true,
+			PlanModifiers: []planmodifier.String{
+				stringplanmodifier.RequiresReplace(),
+			},
+			Validators: r.definition.TagValidators,
+		}
+	}
+	for name, attribute := range r.definition.Attributes {
+		attributes[name] = attribute
+	}
+
+	response.Schema = schema.Schema{
+		MarkdownDescription: r.definition.Description,
+		Attributes:          attributes,
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Create: true,
+				Read:   true,
+				Update: true,
+				Delete: true,
+			}),
+		},
+	}
+}
+
+func (r *typedResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	vyosConfig, ok := req.ProviderData.(*vyos.VyosConfig)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *vyos.VyosConfig, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.vyosConfig = vyosConfig
+}
+
+func (r *typedResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data types.Object
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var timeout timeouts.Value
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("timeouts"), &timeout)...)
+	createTimeout, diags := timeout.Create(ctx, defaultConfigTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, createTimeout)
+	defer cancel()
+
+	components := r.path(data)
+
+	// The resource manages its subtree authoritatively, so taking over
+	// existing configuration, such as the `service ssh` most routers have,
+	// would silently delete the leaves it does not declare. It has to be
+	// imported instead, which shows them in the plan.
+	attribute := path.Root("id")
+	if r.definition.Tag {
+		attribute = path.Root("name")
+	}
+	existing, err := r.vyosConfig.Show(ctx, components)
+	if err != nil {
+		addVyosError(&resp.Diagnostics, attribute, err)
+		return
+	}
+	if existing != nil {
+		resp.Diagnostics.AddAttributeError(
+			attribute,
+			"Configuration already exists",
+			fmt.Sprintf("Configuration path '%s' already exists, try a resource import instead.", vyos.FormatPath(components)),
+		)
+		return
+	}
+
+	tflog.Info(ctx, "Setting "+vyos.FormatPath(components))
+
+	if err := r.vyosConfig.Apply(ctx, components, typedConfigTree(data.Attributes())); err != nil {
+		addVyosError(&resp.Diagnostics, path.Root("id"), err)
+		return
+	}
+
+	data, diags = withAttribute(ctx, data, "id", types.StringValue(vyos.FormatPath(components)))
+	resp.Diagnostics.Append(diags...)
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, data)...)
+}
+
+func (r *typedResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data types.Object
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var timeout timeouts.Value
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("timeouts"), &timeout)...)
+	readTimeout, diags := timeout.Read(ctx, defaultConfigTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, readTimeout)
+	defer cancel()
+
+	components := r.path(data)
+	tflog.Info(ctx, "Reading "+vyos.FormatPath(components))
+
+	config, err := r.vyosConfig.Show(ctx, components)
+	if err != nil {
+		addVyosError(&resp.Diagnostics, path.Root("id"), err)
+		return
+	}
+	if config == nil {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	values := map[string]attr.Value{}
+	for name, value := range data.Attributes() {
+		values[name] = value
+	}
+	for name, attributeType := range data.AttributeTypes(ctx) {
+		if typedResourceAttributes[name] {
+			continue
+		}
+		values[name], diags = typedValue(ctx, attributeType, typedChild(config, name), path.Root(name))
+		resp.Diagnostics.Append(diags...)
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	data, diags = types.ObjectValue(data.AttributeTypes(ctx), values)
+	resp.Diagnostics.Append(diags...)
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, data)...)
+}
+
+func (r *typedResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data types.Object
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var timeout timeouts.Value
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("timeouts"), &timeout)...)
+	updateTimeout, diags := timeout.Update(ctx, defaultConfigTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, updateTimeout)
+	defer cancel()
+
+	components := r.path(data)
+	tflog.Info(ctx, "Updating "+vyos.FormatPath(components))
+
+	if err := r.vyosConfig.Apply(ctx, components, typedConfigTree(data.Attributes())); err != nil {
+		addVyosError(&resp.Diagnostics, path.Root("id"), err)
+		return
+	}
+
+	// Save updated plan into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, data)...)
+}
+
+func (r *typedResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data types.Object
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var timeout timeouts.Value
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("timeouts"), &timeout)...)
+	deleteTimeout, diags := timeout.Delete(ctx, defaultConfigTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, deleteTimeout)
+	defer cancel()
+
+	components := r.path(data)
+	tflog.Info(ctx, "Deleting "+vyos.FormatPath(components))
+
+	if err := r.vyosConfig.Apply(ctx, components, nil); err != nil {
+		addVyosError(&resp.Diagnostics, path.Root("id"), err)
+		return
+	}
+}
+
+// ImportState imports a tag resource by its name, such as `dum0`, and a
+// singleton by its configuration path.
+func (r *typedResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	components := r.definition.Path
+	if r.definition.Tag {
+		components = append(append([]string{}, components...), req.ID)
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("name"), req.ID)...)
+	}
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), vyos.FormatPath(components))...)
+}
+
+// path returns the configuration path of the resource described by data.
+func (r *typedResource) path(data types.Object) []string {
+	components := append([]string{}, r.definition.Path...)
+	if r.definition.Tag {
+		name, _ := data.Attributes()["name"].(types.String)
+		components = append(components, name.ValueString())
+	}
+	return components
+}
+
+// withAttribute returns object with the attribute name set to value.
+func withAttribute(ctx context.Context, object types.Object, name string, value attr.Value) (types.Object, diag.Diagnostics) {
+	values := map[string]attr.Value{}
+	for key, existing := range object.Attributes() {
+		values[key] = existing
+	}
+	values[name] = value
+	return types.ObjectValue(object.AttributeTypes(ctx), values)
+}
+
+// typedNodeName returns the configuration node set by an attribute.
+func typedNodeName(attribute string) string {
+	return strings.ReplaceAll(attribute, "_", "-")
+}
+
+// typedChild returns the configuration of the node set by attribute under
+// config, nil when it is not set.
+func typedChild(config any, attribute string) any {
+	tree, ok := config.(map[string]any)
+	if !ok {
+		return nil
+	}
+	return tree[typedNodeName(attribute)]
+}
+
+// typedString returns the value of the leaf set by attribute under config,
+// null when it is not set.
+func typedString(config any, attribute string) types.String {
+	values := typedStrings(config, attribute)
+	if len(values) == 0 {
+		return types.StringNull()
+	}
+	return types.StringValue(values[0])
+}
+
+// typedStrings returns the values of the leaf set by attribute under config,
+// whether VyOS returned a single value as a string or several as a list.
+func typedStrings(config any, attribute string) []string {
+	child := typedChild(config, attribute)
+	if child == nil {
+		return nil
+	}
+	var values []string
+	for _, leaf := range vyos.Leaves(vyos.Normalize(child)) {
+		if len(leaf.Path) == 0 {
+			values = append(values, leaf.Value)
+		}
+	}
+	return values
+}
+
+// setTypedString sets the leaf of attribute in tree to value, unless value
+// is null or unknown.
+func setTypedString(tree map[string]any, attribute string, value types.String) {
+	if value.IsNull() || value.IsUnknown() {
+		return
+	}
+	tree[typedNodeName(attribute)] = value.ValueString()
+}
+
+// typedConfigTree converts the attributes of a typed resource into the
+// configuration tree they set, leaving out null and unknown attributes.
+func typedConfigTree(attributes map[string]attr.Value) map[string]any {
+	tree := map[string]any{}
+	for name, value := range attributes {
+		if typedResourceAttributes[name] {
+			continue
+		}
+		if config, ok := typedConfig(value); ok {
+			tree[typedNodeName(name)] = config
+		}
+	}
+	return tree
+}
+
+func typedConfig(value attr.Value) (any, bool) {
+	if value.IsNull() || value.IsUnknown() {
+		return nil, false
+	}
+
+	switch v := value.(type) {
+	case basetypes.StringValue:
+		return v.ValueString(), true
+	case basetypes.Int64Value:
+		return strconv.FormatInt(v.ValueInt64(), 10), true
+	case basetypes.BoolValue:
+		return map[string]any{}, v.ValueBool()
+	case basetypes.SetValue:
+		return typedConfigList(v.Elements())
+	case basetypes.ListValue:
+		return typedConfigList(v.Elements())
+	case basetypes.ObjectValue:
+		tree := map[string]any{}
+		for name, child := range v.Attributes() {
+			if config, ok := typedConfig(child); ok {
+				tree[typedNodeName(name)] = config
+			}
+		}
+		return tree, true
+	case basetypes.MapValue:
+		tree := map[string]any{}
+		for tag, child := range v.Elements() {
+			if config, ok := typedConfig(child); ok {
+				tree[tag] = config
+			}
+		}
+		return tree, true
+	}
+	return nil, false
+}
+
+func typedConfigList(elements []attr.Value) (any, bool) {
+	values := []any{}
+	for _, element := range elements {
+		if config, ok := typedConfig(element); ok {
+			values = append(values, config)
+		}
+	}
+	return values, len(values) > 0
+}
+
+// typedValue converts the configuration of a node into the value of an
+// attribute of type attributeType.
+func typedValue(ctx context.Context, attributeType attr.Type, config any, attributePath path.Path) (attr.Value, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	// Valueless nodes are false rather than null when missing, matching the
+	// default of their attributes.
+	if _, ok := attributeType.(basetypes.BoolType); ok {
+		return types.BoolValue(config != nil), diags
+	}
+
+	if config == nil {
+		value, err := attributeType.ValueFromTerraform(ctx, tftypes.NewValue(attributeType.TerraformType(ctx), nil))
+		if err != nil {
+			diags.AddAttributeError(attributePath, "Unable to read configuration", err.Error())
+		}
+		return value, diags
+	}
+
+	leaves := vyos.Leaves(vyos.Normalize(config))
+
+	switch t := attributeType.(type) {
+	case basetypes.StringType:
+		if len(leaves) > 0 {
+			return types.StringValue(leaves[0].Value), diags
+		}
+		return types.StringValue(""), diags
+
+	case basetypes.Int64Type:
+		number, err := strconv.ParseInt(fmt.Sprint(config), 10, 64)
+		if err != nil {
+			diags.AddAttributeError(attributePath, "Unexpected configuration value", fmt.Sprintf("Expected a number, got %q.", config))
+		}
+		return types.Int64Value(number), diags
+
+	case basetypes.SetType:
+		elements := []attr.Value{}
+		for _, leaf := range leaves {
+			elements = append(elements, types.StringValue(leaf.Value))
+		}
+		return types.SetValue(t.ElemType, elements)
+
+	case basetypes.ObjectType:
+		values := map[string]attr.Value{}
+		for name, childType := range t.AttrTypes {
+			var childDiags diag.Diagnostics
+			values[name], childDiags = typedValue(ctx, childType, typedChild(config, name), attributePath.AtName(name))
+			diags.Append(childDiags...)
+		}
+		object, objectDiags := types.ObjectValue(t.AttrTypes, values)
+		diags.Append(objectDiags...)
+		return object, diags
+
+	case basetypes.MapType:
+		tree, _ := config.(map[string]any)
+		tags := make([]string, 0, len(tree))
+		for tag := range tree {
+			tags = append(tags, tag)
+		}
+		sort.Strings(tags)
+
+		elements := map[string]attr.Value{}
+		for _, tag := range tags {
+			var elementDiags diag.Diagnostics
+			elements[tag], elementDiags = typedValue(ctx, t.ElemType, tree[tag], attributePath.AtMapKey(tag))
+			diags.Append(elementDiags...)
+		}
+		value, mapDiags := types.MapValue(t.ElemType, elements)
+		diags.Append(mapDiags...)
+		return value, diags
+	}
+
+	diags.AddAttributeError(attributePath, "Unsupported attribute type", fmt.Sprintf("Attributes of type %s cannot be read from the configuration.", attributeType))
+	return nil, diags
+}
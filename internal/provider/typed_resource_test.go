@@ -0,0 +1,194 @@
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccInterfaceDummyResource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			if server := testAccPreCheck(t); server != nil {
+				server.SetMulti("address")
+			}
+		},
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: testAccInterfaceDummyResourceConfig(`["10.0.0.1/24", "10.0.1.1/24"]`, "Managed by Terraform"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("vyos_interface_dummy.test", "id", "interfaces dummy dum0"),
+					resource.TestCheckResourceAttr("vyos_interface_dummy.test", "address.#", "2"),
+					resource.TestCheckResourceAttr("vyos_interface_dummy.test", "disable", "false"),
+					resource.TestCheckResourceAttr("data.vyos_config.test", "value", `{"address":["10.0.0.1/24","10.0.1.1/24"],"description":"Managed by Terraform","mtu":"1500"}`),
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:            "vyos_interface_dummy.test",
+				ImportState:             true,
+				ImportStateId:           "dum0",
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{},
+			},
+			// Update and Read testing, the removed address is deleted
+			{
+				Config: testAccInterfaceDummyResourceConfig(`["10.0.0.1/24"]`, "Updated by Terraform"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("vyos_interface_dummy.test", "address.#", "1"),
+					resource.TestCheckResourceAttr("data.vyos_config.test", "value", `{"address":"10.0.0.1/24","description":"Updated by Terraform","mtu":"1500"}`),
+				),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func TestAccInterfaceDummyResourceValidation(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "vyos_interface_dummy" "test" {
+  name = "eth0"
+}
+`,
+				ExpectError: regexp.MustCompile(`Dummy\s+interface\s+must\s+be\s+named\s+dumN`),
+			},
+			{
+				Config: `
+resource "vyos_interface_dummy" "test" {
+  name = "dum0"
+  mtu  = 20
+}
+`,
+				ExpectError: regexp.MustCompile(`must\s+be\s+between\s+68\s+and\s+16000`),
+			},
+		},
+	})
+}
+
+func TestAccStaticHostMappingResource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			if server := testAccPreCheck(t); server != nil {
+				server.SetMulti("inet", "alias")
+			}
+		},
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: `
+resource "vyos_static_host_mapping" "test" {
+  name  = "nas.example.com"
+  inet  = ["192.0.2.20"]
+  alias = ["nas"]
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("vyos_static_host_mapping.test", "id", "system static-host-mapping host-name nas.example.com"),
+					resource.TestCheckResourceAttr("vyos_static_host_mapping.test", "inet.#", "1"),
+					resource.TestCheckResourceAttr("vyos_static_host_mapping.test", "alias.#", "1"),
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:            "vyos_static_host_mapping.test",
+				ImportState:             true,
+				ImportStateId:           "nas.example.com",
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{},
+			},
+			// Update and Read testing
+			{
+				Config: `
+resource "vyos_static_host_mapping" "test" {
+  name = "nas.example.com"
+  inet = ["192.0.2.20", "2001:db8::20"]
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("vyos_static_host_mapping.test", "inet.#", "2"),
+					resource.TestCheckNoResourceAttr("vyos_static_host_mapping.test", "alias"),
+				),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func TestAccServiceSshResource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			if server := testAccPreCheck(t); server != nil {
+				server.SetConfig(map[string]any{
+					"service": map[string]any{"ssh": map[string]any{"port": "22"}},
+				})
+			}
+		},
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create refuses to take over the existing service
+			{
+				Config:      testAccServiceSshResourceConfig,
+				ExpectError: regexp.MustCompile(`Configuration path 'service ssh' already exists, try a resource\s+import\s+instead`),
+			},
+			// ImportState testing
+			{
+				Config:             testAccServiceSshResourceConfig,
+				ResourceName:       "vyos_service_ssh.test",
+				ImportState:        true,
+				ImportStateId:      "service ssh",
+				ImportStatePersist: true,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("vyos_service_ssh.test", "port", "22"),
+				),
+			},
+			// Update and Read testing
+			{
+				Config: testAccServiceSshResourceConfig,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("vyos_service_ssh.test", "id", "service ssh"),
+					resource.TestCheckResourceAttr("vyos_service_ssh.test", "port", "2222"),
+					resource.TestCheckResourceAttr("vyos_service_ssh.test", "disable_password_authentication", "true"),
+					resource.TestCheckResourceAttr("vyos_service_ssh.test", "dynamic_protection.threshold", "10"),
+				),
+			},
+		},
+	})
+}
+
+const testAccServiceSshResourceConfig = `
+resource "vyos_service_ssh" "test" {
+  port                            = 2222
+  disable_password_authentication = true
+
+  dynamic_protection = {
+    threshold = 10
+  }
+}
+`
+
+func testAccInterfaceDummyResourceConfig(addresses string, description string) string {
+	return fmt.Sprintf(`
+resource "vyos_interface_dummy" "test" {
+  name        = "dum0"
+  address     = %[1]s
+  description = %[2]q
+  mtu         = 1500
+}
+
+data "vyos_config" "test" {
+  path = "interfaces dummy dum0"
+
+  depends_on = [vyos_interface_dummy.test]
+}
+`, addresses, description)
+}
@@ -0,0 +1,107 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/TGNThump/terraform-provider-vyos/internal/vyos"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces
+var _ datasource.DataSource = &VersionDataSource{}
+var _ datasource.DataSourceWithConfigure = &VersionDataSource{}
+
+func NewVersionDataSource() datasource.DataSource {
+	return &VersionDataSource{}
+}
+
+// VersionDataSource defines the data source implementation.
+type VersionDataSource struct {
+	vyosConfig *vyos.VyosConfig
+}
+
+// VersionDataSourceModel describes the data source data model.
+type VersionDataSourceModel struct {
+	Version types.String `tfsdk:"version"`
+	Major   types.Int64  `tfsdk:"major"`
+	Minor   types.Int64  `tfsdk:"minor"`
+	Id      types.String `tfsdk:"id"`
+}
+
+func (d *VersionDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_version"
+}
+
+func (d *VersionDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "The VyOS version the router runs, as detected by the provider.",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Version identifier",
+			},
+			"version": schema.StringAttribute{
+				MarkdownDescription: "Version reported by the router, such as `1.3.8` or `1.5-rolling-202410090007`.",
+				Computed:            true,
+			},
+			"major": schema.Int64Attribute{
+				MarkdownDescription: "Major version number, such as `1`.",
+				Computed:            true,
+			},
+			"minor": schema.Int64Attribute{
+				MarkdownDescription: "Minor version number, such as `4`.",
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (d *VersionDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	vyosConfig, ok := req.ProviderData.(*vyos.VyosConfig)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *vyos.VyosConfig, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.vyosConfig = vyosConfig
+}
+
+func (d *VersionDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data VersionDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	version, err := d.vyosConfig.DetectVersion(ctx)
+	if err != nil {
+		addVyosError(&resp.Diagnostics, path.Root("version"), err)
+		return
+	}
+
+	data.Id = types.StringValue(version.Raw)
+	data.Version = types.StringValue(version.Raw)
+	data.Major = types.Int64Value(int64(version.Major))
+	data.Minor = types.Int64Value(int64(version.Minor))
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
@@ -0,0 +1,57 @@
+package provider
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccVersionDataSource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			if server := testAccPreCheck(t); server != nil {
+				server.SetVersion("1.5-rolling-202410090007")
+			}
+		},
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Read testing
+			{
+				Config: testAccVersionDataSourceConfig,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet("data.vyos_version.test", "version"),
+					resource.TestMatchResourceAttr("data.vyos_version.test", "major", regexp.MustCompile(`^\d+$`)),
+					resource.TestMatchResourceAttr("data.vyos_version.test", "minor", regexp.MustCompile(`^\d+$`)),
+				),
+			},
+		},
+	})
+}
+
+func TestAccVersionUnsupportedCommitConfirm(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			server := testAccPreCheck(t)
+			if server == nil {
+				t.Skip("older VyOS versions are only tested against the fake server")
+			}
+			server.SetVersion("1.3.8")
+		},
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+provider "vyos" {
+  commit_confirm_minutes = 5
+}
+` + testAccConfigResourceConfig("system host-name", `jsonencode("router")`),
+				ExpectError: regexp.MustCompile(`commit-confirm requires VyOS\s+1.4 or later`),
+			},
+		},
+	})
+}
+
+const testAccVersionDataSourceConfig = `
+data "vyos_version" "test" {}
+`
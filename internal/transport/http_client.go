@@ -0,0 +1,62 @@
+package transport
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+const (
+	// DefaultConnectTimeout is how long connecting to the router may take.
+	DefaultConnectTimeout = 30 * time.Second
+	// DefaultRequestTimeout is how long a single API request may take,
+	// including the commit it triggers.
+	DefaultRequestTimeout = 10 * time.Minute
+)
+
+// HttpClientOptions are the settings of the HTTP client used to reach the
+// router.
+type HttpClientOptions struct {
+	TlsConfig      *tls.Config
+	ConnectTimeout time.Duration
+	RequestTimeout time.Duration
+	// ProxyUrl is an http, https or socks5 proxy, nil to use the proxy set
+	// by the HTTPS_PROXY environment variables.
+	ProxyUrl *url.URL
+}
+
+// NewHttpClient builds the HTTP client used to reach the router.
+func NewHttpClient(options HttpClientOptions) *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = options.TlsConfig
+	transport.DialContext = (&net.Dialer{
+		Timeout:   options.ConnectTimeout,
+		KeepAlive: 30 * time.Second,
+	}).DialContext
+	if options.ProxyUrl != nil {
+		transport.Proxy = http.ProxyURL(options.ProxyUrl)
+	}
+
+	return &http.Client{Transport: transport, Timeout: options.RequestTimeout}
+}
+
+// ParseProxyUrl parses the URL of a proxy to reach the router through.
+func ParseProxyUrl(value string) (*url.URL, error) {
+	proxyUrl, err := url.Parse(value)
+	if err != nil {
+		return nil, err
+	}
+
+	switch proxyUrl.Scheme {
+	case "http", "https", "socks5":
+	default:
+		return nil, fmt.Errorf("unsupported proxy scheme %q, expected http, https or socks5", proxyUrl.Scheme)
+	}
+	if proxyUrl.Host == "" {
+		return nil, fmt.Errorf("proxy URL %q has no host", value)
+	}
+	return proxyUrl, nil
+}
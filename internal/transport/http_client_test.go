@@ -0,0 +1,55 @@
+package transport
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestHttpClientProxy(t *testing.T) {
+	proxied := ""
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied = r.URL.String()
+	}))
+	t.Cleanup(proxy.Close)
+
+	proxyUrl, err := ParseProxyUrl(proxy.URL)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	httpClient := NewHttpClient(HttpClientOptions{
+		ConnectTimeout: time.Second,
+		RequestTimeout: 5 * time.Second,
+		ProxyUrl:       proxyUrl,
+	})
+
+	resp, err := httpClient.Get("http://vyos.invalid/retrieve")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	resp.Body.Close()
+
+	if proxied != "http://vyos.invalid/retrieve" {
+		t.Errorf("expected the request to go through the proxy, got %q", proxied)
+	}
+}
+
+func TestParseProxyUrl(t *testing.T) {
+	cases := map[string]bool{
+		"http://bastion:3128":   true,
+		"https://bastion:3128":  true,
+		"socks5://bastion:1080": true,
+		"ftp://bastion":         false,
+		"bastion:1080":          false,
+		"socks5://":             false,
+		"http://[::1":           false,
+	}
+
+	for value, valid := range cases {
+		if _, err := ParseProxyUrl(value); (err == nil) != valid {
+			t.Errorf("ParseProxyUrl(%q): unexpected result %v", value, err)
+		}
+	}
+}
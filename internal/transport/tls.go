@@ -0,0 +1,86 @@
+// Package transport builds the HTTP client used to reach the VyOS API, shared
+// by the provider and the export subcommand so both verify the router, use
+// client certificates and go through proxies the same way.
+package transport
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// TlsOptions are the settings used to verify the router and to authenticate
+// with a client certificate.
+type TlsOptions struct {
+	Insecure   bool
+	CaCertFile string
+	// CaCertPem holds PEM certificates, trusted along with CaCertFile.
+	CaCertPem string
+	// ClientCert and ClientKey are PEM data or the files holding it.
+	ClientCert string
+	ClientKey  string
+	ServerName string
+}
+
+// TlsConfig builds the TLS configuration of the HTTP transport.
+func (o TlsOptions) TlsConfig() (*tls.Config, error) {
+	config := &tls.Config{
+		InsecureSkipVerify: o.Insecure,
+		ServerName:         o.ServerName,
+	}
+
+	if o.CaCertFile != "" || o.CaCertPem != "" {
+		pool := x509.NewCertPool()
+
+		if o.CaCertFile != "" {
+			pem, err := os.ReadFile(o.CaCertFile)
+			if err != nil {
+				return nil, fmt.Errorf("unable to read CA certificate file: %w", err)
+			}
+			if !pool.AppendCertsFromPEM(pem) {
+				return nil, fmt.Errorf("no PEM certificates found in %s", o.CaCertFile)
+			}
+		}
+
+		if o.CaCertPem != "" && !pool.AppendCertsFromPEM([]byte(o.CaCertPem)) {
+			return nil, errors.New("no PEM certificates found in the CA certificate PEM")
+		}
+
+		config.RootCAs = pool
+	}
+
+	if o.ClientCert != "" || o.ClientKey != "" {
+		if o.ClientCert == "" || o.ClientKey == "" {
+			return nil, errors.New("the client certificate and key must be set together")
+		}
+
+		cert, err := PemOrFile(o.ClientCert)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read client certificate: %w", err)
+		}
+		key, err := PemOrFile(o.ClientKey)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read client key: %w", err)
+		}
+
+		pair, err := tls.X509KeyPair(cert, key)
+		if err != nil {
+			return nil, fmt.Errorf("invalid client certificate: %w", err)
+		}
+		config.Certificates = []tls.Certificate{pair}
+	}
+
+	return config, nil
+}
+
+// PemOrFile returns value if it holds PEM data, and otherwise reads the file
+// it names.
+func PemOrFile(value string) ([]byte, error) {
+	if strings.Contains(value, "-----BEGIN") {
+		return []byte(value), nil
+	}
+	return os.ReadFile(value)
+}
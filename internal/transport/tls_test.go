@@ -0,0 +1,112 @@
+package transport
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestTlsConfig(t *testing.T) {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	server.TLS = &tls.Config{ClientAuth: tls.RequestClientCert}
+	server.StartTLS()
+	t.Cleanup(server.Close)
+
+	caPem := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}))
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	if err := os.WriteFile(caFile, []byte(caPem), 0600); err != nil {
+		t.Fatal(err)
+	}
+	clientCert, clientKey := testClientCertificate(t)
+
+	cases := map[string]struct {
+		options TlsOptions
+		valid   bool
+	}{
+		"system CAs":  {TlsOptions{}, false},
+		"insecure":    {TlsOptions{Insecure: true}, true},
+		"CA file":     {TlsOptions{CaCertFile: caFile}, true},
+		"CA PEM":      {TlsOptions{CaCertPem: caPem}, true},
+		"server name": {TlsOptions{CaCertPem: caPem, ServerName: "example.com"}, true},
+		"wrong name":  {TlsOptions{CaCertPem: caPem, ServerName: "vyos.invalid"}, false},
+		"client cert": {TlsOptions{CaCertPem: caPem, ClientCert: clientCert, ClientKey: clientKey}, true},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			config, err := c.options.TlsConfig()
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			transport := http.DefaultTransport.(*http.Transport).Clone()
+			transport.TLSClientConfig = config
+			resp, err := (&http.Client{Transport: transport, Timeout: 5 * time.Second}).Get(server.URL)
+			if err == nil {
+				resp.Body.Close()
+			}
+			if (err == nil) != c.valid {
+				t.Errorf("unexpected result: %v", err)
+			}
+
+			if c.options.ClientCert != "" && err == nil && len(config.Certificates) != 1 {
+				t.Errorf("expected the client certificate to be used")
+			}
+		})
+	}
+}
+
+func TestTlsConfigErrors(t *testing.T) {
+	clientCert, _ := testClientCertificate(t)
+
+	cases := map[string]TlsOptions{
+		"missing CA file": {CaCertFile: filepath.Join(t.TempDir(), "missing.pem")},
+		"invalid CA PEM":  {CaCertPem: "not a certificate"},
+		"missing key":     {ClientCert: clientCert},
+	}
+
+	for name, options := range cases {
+		if _, err := options.TlsConfig(); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+// testClientCertificate returns a self-signed certificate and its key as PEM.
+func testClientCertificate(t *testing.T) (string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "terraform"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cert := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPem := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer})
+	return string(cert), string(keyPem)
+}
@@ -0,0 +1,47 @@
+package vyos
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Apply turns the configuration under path into value in a single commit,
+// changing only the leaves which differ from the router, so nodes which stay
+// the same are not touched. A nil value deletes the configuration under path.
+func (vc *VyosConfig) Apply(ctx context.Context, path []string, value any) error {
+	current, err := vc.Show(ctx, path)
+	if err != nil {
+		return err
+	}
+
+	payload := Diff(path, current, value, current)
+	if len(payload) == 0 {
+		return nil
+	}
+
+	tflog.Info(ctx, fmt.Sprintf("Applying %d operations to %s", len(payload), FormatPath(path)), map[string]interface{}{
+		"operations": payload,
+	})
+
+	return vc.Configure(ctx, payload)
+}
+
+// AddValue adds value to the leaf at path, keeping the values it already
+// has, so several resources can contribute to the same leaf.
+func (vc *VyosConfig) AddValue(ctx context.Context, path []string, value string) error {
+	return vc.Configure(ctx, []map[string]any{
+		{"op": "set", "path": path, "value": value},
+	})
+}
+
+// RemoveValue removes value from the leaf at path, keeping its other values.
+// Removing a value the leaf does not have does nothing.
+func (vc *VyosConfig) RemoveValue(ctx context.Context, path []string, value string) error {
+	current, err := vc.Show(ctx, path)
+	if err != nil {
+		return err
+	}
+	return vc.Configure(ctx, Diff(path, value, nil, current))
+}
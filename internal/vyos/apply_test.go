@@ -0,0 +1,106 @@
+package vyos
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestApply(t *testing.T) {
+	ctx := context.Background()
+	vc, server := newTestConfig(t, true)
+	server.SetConfig(map[string]any{
+		"service": map[string]any{
+			"ssh": map[string]any{"port": "22", "disable-host-validation": map[string]any{}},
+		},
+	})
+	server.SetMulti("listen-address")
+
+	path := []string{"service", "ssh"}
+	value := map[string]any{"port": "2222", "listen-address": []any{"192.0.2.1", "192.0.2.2"}}
+	if err := vc.Apply(ctx, path, value); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	config, _ := vc.Show(ctx, path)
+	if !reflect.DeepEqual(Normalize(config), Normalize(value)) {
+		t.Errorf("unexpected config: %v", config)
+	}
+
+	var operations any
+	for _, request := range server.Requests() {
+		if request.Endpoint == "configure" {
+			operations = request.Payload
+		}
+	}
+	expected := []any{
+		map[string]any{"op": "delete", "path": []any{"service", "ssh", "disable-host-validation"}},
+		map[string]any{"op": "delete", "path": []any{"service", "ssh", "port"}, "value": "22"},
+		map[string]any{"op": "set", "path": []any{"service", "ssh", "listen-address"}, "value": "192.0.2.1"},
+		map[string]any{"op": "set", "path": []any{"service", "ssh", "listen-address"}, "value": "192.0.2.2"},
+		map[string]any{"op": "set", "path": []any{"service", "ssh", "port"}, "value": "2222"},
+	}
+	if !reflect.DeepEqual(operations, expected) {
+		t.Errorf("unexpected operations:\n%v\nexpected\n%v", operations, expected)
+	}
+
+	// Applying the same configuration again sends nothing.
+	configures := server.RequestCount("configure")
+	if err := vc.Apply(ctx, path, value); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if count := server.RequestCount("configure"); count != configures {
+		t.Errorf("expected no configure, got %d", count-configures)
+	}
+
+	if err := vc.Apply(ctx, path, nil); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if config, _ := vc.Show(ctx, path); config != nil {
+		t.Errorf("expected the configuration to be deleted, got %v", config)
+	}
+}
+
+func TestAddAndRemoveValue(t *testing.T) {
+	ctx := context.Background()
+	vc, server := newTestConfig(t, true)
+	server.SetConfig(map[string]any{
+		"firewall": map[string]any{
+			"group": map[string]any{
+				"network-group": map[string]any{
+					"ADMIN": map[string]any{"network": "192.0.2.0/24"},
+				},
+			},
+		},
+	})
+	server.SetMulti("network")
+
+	path := []string{"firewall", "group", "network-group", "ADMIN", "network"}
+	if err := vc.AddValue(ctx, path, "198.51.100.0/24"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	config, _ := vc.Show(ctx, path)
+	if expected := []any{"192.0.2.0/24", "198.51.100.0/24"}; !reflect.DeepEqual(Normalize(config), expected) {
+		t.Errorf("unexpected config: %v", config)
+	}
+
+	if err := vc.RemoveValue(ctx, path, "192.0.2.0/24"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if err := vc.RemoveValue(ctx, path, "198.51.100.0/24"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if config, _ := vc.Show(ctx, path); config != nil {
+		t.Errorf("expected every value to be removed, got %v", config)
+	}
+
+	// Removing a missing value sends nothing.
+	configures := server.RequestCount("configure")
+	if err := vc.RemoveValue(ctx, path, "203.0.113.0/24"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if count := server.RequestCount("configure"); count != configures {
+		t.Errorf("expected no configure, got %d", count-configures)
+	}
+}
@@ -0,0 +1,227 @@
+package vyos
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Backend carries out the requests of a VyosConfig on the router. Errors are
+// returned as an Error of the matching kind.
+type Backend interface {
+	// Retrieve returns the whole configuration tree, nil when it is empty.
+	Retrieve(ctx context.Context) (map[string]any, error)
+	// Configure applies a list of `set` and `delete` operations in a single
+	// commit. A positive confirmMinutes commits with commit-confirm, rolling
+	// the change back after that many minutes unless it is confirmed.
+	Configure(ctx context.Context, payload []map[string]any, confirmMinutes int64) error
+	// Confirm confirms a commit made with commit-confirm.
+	Confirm(ctx context.Context) error
+	// Save saves the running configuration to file, or to the startup
+	// configuration when file is empty.
+	Save(ctx context.Context, file string) error
+	// Show runs an operational mode `show` command and returns its output.
+	// path holds the words after `show`.
+	Show(ctx context.Context, path []string) (string, error)
+	// Ping checks that the router still answers.
+	Ping(ctx context.Context) error
+}
+
+// apiBackend talks to the router through the VyOS HTTP API.
+type apiBackend struct {
+	httpClient *http.Client
+	endpoint   string
+	key        string
+	// pingClient opens a new connection for every request.
+	pingClient *http.Client
+}
+
+// NewApiBackend returns a Backend using the VyOS HTTP API of the router at
+// endpoint, authenticating with the API key.
+func NewApiBackend(httpClient *http.Client, endpoint string, key string) Backend {
+	return &apiBackend{
+		httpClient: httpClient,
+		endpoint:   strings.TrimSuffix(endpoint, "/"),
+		key:        key,
+		pingClient: withoutKeepAlives(httpClient),
+	}
+}
+
+// withoutKeepAlives returns a copy of httpClient which does not reuse
+// connections.
+func withoutKeepAlives(httpClient *http.Client) *http.Client {
+	transport, ok := httpClient.Transport.(*http.Transport)
+	if !ok {
+		transport = http.DefaultTransport.(*http.Transport)
+	}
+	transport = transport.Clone()
+	transport.DisableKeepAlives = true
+
+	pingClient := *httpClient
+	pingClient.Transport = transport
+	return &pingClient
+}
+
+// apiResponse is the body of every VyOS HTTP API answer.
+type apiResponse struct {
+	Success bool    `json:"success"`
+	Data    any     `json:"data"`
+	Error   *string `json:"error"`
+}
+
+// request posts payload to an API endpoint, classifying any error. changes
+// holds the operations of requests which change the configuration.
+func (b *apiBackend) request(ctx context.Context, endpoint string, payload any, changes []map[string]any) (any, error) {
+	return b.post(ctx, b.httpClient, endpoint, payload, changes)
+}
+
+func (b *apiBackend) post(ctx context.Context, httpClient *http.Client, endpoint string, payload any, changes []map[string]any) (any, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request payload: %w", err)
+	}
+
+	form := url.Values{"key": {b.key}, "data": {string(data)}}
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, b.endpoint+"/"+endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	response, err := httpClient.Do(request)
+	if err != nil {
+		return nil, classify(err, changes)
+	}
+	defer response.Body.Close()
+
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, classify(err, changes)
+	}
+
+	var answer apiResponse
+	if err := json.Unmarshal(body, &answer); err != nil {
+		return nil, classifyStatus(response.StatusCode, err, changes)
+	}
+	if answer.Error != nil {
+		return nil, classifyStatus(response.StatusCode, errors.New(*answer.Error), changes)
+	}
+	if response.StatusCode >= http.StatusInternalServerError {
+		return nil, classifyStatus(response.StatusCode, errors.New(response.Status), changes)
+	}
+	return answer.Data, nil
+}
+
+func (b *apiBackend) Retrieve(ctx context.Context) (map[string]any, error) {
+	resp, err := b.request(ctx, "retrieve", map[string]any{
+		"op":   "showConfig",
+		"path": []string{},
+	}, nil)
+	if err != nil {
+		if strings.Contains(err.Error(), "could not fetch config") {
+			// If we get an empty path error, consume it and return nil
+			return nil, nil
+		} else {
+			return nil, err
+		}
+	}
+
+	obj, ok := resp.(map[string]any)
+	if !ok {
+		return nil, errMalformedResponse
+	}
+	return obj, nil
+}
+
+func (b *apiBackend) Configure(ctx context.Context, payload []map[string]any, confirmMinutes int64) error {
+	if confirmMinutes > 0 {
+		confirmPayload := make([]map[string]any, len(payload))
+		for i, op := range payload {
+			confirmOp := make(map[string]any, len(op)+1)
+			for key, value := range op {
+				confirmOp[key] = value
+			}
+			confirmOp["confirm_time"] = confirmMinutes
+			confirmPayload[i] = confirmOp
+		}
+		payload = confirmPayload
+	}
+
+	_, err := b.request(ctx, "configure", payload, payload)
+	return err
+}
+
+func (b *apiBackend) Confirm(ctx context.Context) error {
+	op := map[string]any{"op": "confirm"}
+	_, err := b.request(ctx, "configure", op, []map[string]any{op})
+	return err
+}
+
+func (b *apiBackend) Save(ctx context.Context, file string) error {
+	payload := map[string]any{"op": "save"}
+	if file != "" {
+		payload["file"] = file
+	}
+	_, err := b.request(ctx, "config-file", payload, nil)
+	return err
+}
+
+func (b *apiBackend) Show(ctx context.Context, path []string) (string, error) {
+	resp, err := b.request(ctx, "show", map[string]any{
+		"op":   "show",
+		"path": path,
+	}, nil)
+	if err != nil {
+		return "", err
+	}
+
+	output, ok := resp.(string)
+	if !ok {
+		return "", errMalformedResponse
+	}
+	return output, nil
+}
+
+func (b *apiBackend) Ping(ctx context.Context) error {
+	// A new connection is opened, as one established before a change can
+	// outlive a change which cuts off the API.
+	_, err := b.post(ctx, b.pingClient, "retrieve", map[string]any{
+		"op":   "exists",
+		"path": []string{"service", "https"},
+	}, nil)
+	return err
+}
+
+// Info returns the version reported by the /info endpoint, which only VyOS
+// 1.5 and later serve.
+func (b *apiBackend) Info(ctx context.Context) (string, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, b.endpoint+"/info", nil)
+	if err != nil {
+		return "", err
+	}
+
+	response, err := b.httpClient.Do(request)
+	if err != nil {
+		return "", classify(err, nil)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("/info answered %s", response.Status)
+	}
+
+	var info struct {
+		Data struct {
+			Version string `json:"version"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(response.Body).Decode(&info); err != nil {
+		return "", classify(err, nil)
+	}
+	return info.Data.Version, nil
+}
@@ -0,0 +1,177 @@
+package vyos
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+const (
+	// DefaultBatchWindow is how long operations are collected from concurrent
+	// resources before they are sent to the router as a single commit.
+	DefaultBatchWindow = 250 * time.Millisecond
+	// DefaultBatchTimeout bounds a batched commit when none of the resources
+	// in it has a deadline of its own.
+	DefaultBatchTimeout = 20 * time.Minute
+)
+
+type batchRequest struct {
+	ctx     context.Context
+	payload []map[string]any
+	done    chan error
+}
+
+// Configure queues a list of `configure` operations. Operations from every
+// caller within the batch window are committed together, followed by a single
+// save, and the result of that commit is returned to each caller.
+//
+// A caller cancelled before the batch is sent is taken out of it. Once the
+// batch has been sent the caller waits for its result, since the router may
+// have applied the operations.
+func (vc *VyosConfig) Configure(ctx context.Context, payload []map[string]any) error {
+	if len(payload) == 0 {
+		return nil
+	}
+
+	request := &batchRequest{
+		ctx:     ctx,
+		payload: payload,
+		done:    make(chan error, 1),
+	}
+
+	vc.batchMutex.Lock()
+	vc.pending = append(vc.pending, request)
+	if len(vc.pending) == 1 {
+		time.AfterFunc(vc.batchWindow, vc.flush)
+	}
+	vc.batchMutex.Unlock()
+
+	select {
+	case err := <-request.done:
+		return err
+	case <-ctx.Done():
+	}
+
+	if vc.withdraw(request) {
+		return ctx.Err()
+	}
+
+	tflog.Warn(ctx, "Cancelled while the batched commit was in progress, waiting for its result")
+	return <-request.done
+}
+
+// withdraw takes request out of the pending batch, reporting whether it was
+// still pending.
+func (vc *VyosConfig) withdraw(request *batchRequest) bool {
+	vc.batchMutex.Lock()
+	defer vc.batchMutex.Unlock()
+
+	for i, pending := range vc.pending {
+		if pending == request {
+			vc.pending = append(vc.pending[:i:i], vc.pending[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+func (vc *VyosConfig) flush() {
+	vc.batchMutex.Lock()
+	var requests []*batchRequest
+	for _, request := range vc.pending {
+		if err := request.ctx.Err(); err != nil {
+			request.done <- err
+			continue
+		}
+		requests = append(requests, request)
+	}
+	vc.pending = nil
+	vc.batchMutex.Unlock()
+
+	if len(requests) == 0 {
+		return
+	}
+
+	ctx, cancel := batchContext(requests)
+	defer cancel()
+
+	tflog.Info(ctx, fmt.Sprintf("Committing %d operations from %d resources", countOperations(requests), len(requests)))
+
+	results := make([]error, len(requests))
+	vc.commitBatch(ctx, requests, results)
+
+	vc.invalidateConfigCache()
+
+	committed := false
+	for _, result := range results {
+		committed = committed || result == nil
+	}
+
+	if committed {
+		if err := vc.SaveIfRequired(ctx); err != nil {
+			for i := range results {
+				if results[i] == nil {
+					results[i] = err
+				}
+			}
+		}
+	}
+
+	for i, request := range requests {
+		request.done <- results[i]
+	}
+}
+
+// commitBatch commits the operations of requests together, storing the
+// result of each request in results. When the router refuses the commit, one
+// bad resource should not fail the whole apply, so the batch is split in
+// halves and each half committed on its own until the error is attributed to
+// the resources which caused it.
+func (vc *VyosConfig) commitBatch(ctx context.Context, requests []*batchRequest, results []error) {
+	var payload []map[string]any
+	for _, request := range requests {
+		payload = append(payload, request.payload...)
+	}
+
+	err := vc.commit(ctx, payload)
+	if err != nil && len(requests) > 1 && KindOf(err) == ErrorCommitValidation {
+		tflog.Warn(ctx, fmt.Sprintf("Batched commit of %d resources failed, splitting it: %s", len(requests), err))
+		half := len(requests) / 2
+		vc.commitBatch(ctx, requests[:half], results[:half])
+		vc.commitBatch(ctx, requests[half:], results[half:])
+		return
+	}
+
+	for i := range results {
+		results[i] = err
+	}
+}
+
+// batchContext returns the context a batch is committed under. The batch
+// belongs to no single resource, so cancelling one of them does not cancel
+// it, and it runs until the latest deadline of its resources. The values of
+// the first context are kept for logging.
+func batchContext(requests []*batchRequest) (context.Context, context.CancelFunc) {
+	var deadline time.Time
+	for _, request := range requests {
+		requestDeadline, ok := request.ctx.Deadline()
+		if !ok {
+			requestDeadline = time.Now().Add(DefaultBatchTimeout)
+		}
+		if requestDeadline.After(deadline) {
+			deadline = requestDeadline
+		}
+	}
+
+	return context.WithDeadline(context.WithoutCancel(requests[0].ctx), deadline)
+}
+
+func countOperations(requests []*batchRequest) int {
+	count := 0
+	for _, request := range requests {
+		count += len(request.payload)
+	}
+	return count
+}
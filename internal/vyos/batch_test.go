@@ -0,0 +1,147 @@
+package vyos
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestConcurrentSetsShareOneCommit(t *testing.T) {
+	ctx := context.Background()
+	vc, server := newTestConfig(t, false)
+
+	var wg sync.WaitGroup
+	errs := make([]error, 20)
+	for i := range errs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs[i] = vc.Set(ctx, []string{"firewall", "name", fmt.Sprintf("TEST%d", i)}, map[string]any{"default-action": "drop"})
+		}(i)
+	}
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			t.Errorf("resource %d: unexpected error: %s", i, err)
+		}
+	}
+
+	if count := server.RequestCount("configure"); count != 1 {
+		t.Errorf("expected a single configure, got %d", count)
+	}
+	if saves := server.Saves(); len(saves) != 1 {
+		t.Errorf("expected a single save, got %v", saves)
+	}
+
+	names, err := vc.Show(ctx, []string{"firewall", "name"})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(names.(map[string]any)) != len(errs) {
+		t.Errorf("unexpected config: %v", names)
+	}
+}
+
+func TestFailedBatchIsAttributedToResource(t *testing.T) {
+	ctx := context.Background()
+	vc, server := newTestConfig(t, true)
+
+	var wg sync.WaitGroup
+	var setErr, deleteErr error
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		setErr = vc.Set(ctx, []string{"system", "host-name"}, "router")
+	}()
+	go func() {
+		defer wg.Done()
+		deleteErr = vc.Delete(ctx, []string{"system", "domain-name"})
+	}()
+	wg.Wait()
+
+	if setErr != nil {
+		t.Errorf("unexpected error: %s", setErr)
+	}
+	if deleteErr == nil {
+		t.Error("expected deleting a missing node to fail")
+	}
+
+	if value, _ := vc.Show(ctx, []string{"system", "host-name"}); value != "router" {
+		t.Errorf("unexpected value: %v", value)
+	}
+	if count := server.RequestCount("configure"); count != 3 {
+		t.Errorf("expected the batch and two individual commits, got %d", count)
+	}
+}
+
+func TestFailedBatchIsSplitInHalves(t *testing.T) {
+	ctx := context.Background()
+	vc, server := newTestConfig(t, true)
+
+	var wg sync.WaitGroup
+	errs := make([]error, 8)
+	for i := range errs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if i == 5 {
+				errs[i] = vc.Delete(ctx, []string{"system", "domain-name"})
+				return
+			}
+			errs[i] = vc.Set(ctx, []string{"firewall", "name", fmt.Sprintf("TEST%d", i)}, map[string]any{"default-action": "drop"})
+		}(i)
+	}
+	wg.Wait()
+
+	for i, err := range errs {
+		if i == 5 && err == nil {
+			t.Error("expected deleting a missing node to fail")
+		} else if i != 5 && err != nil {
+			t.Errorf("resource %d: unexpected error: %s", i, err)
+		}
+	}
+
+	// The batch, then halves of 4, 2 and 1 resources around the bad one.
+	if count := server.RequestCount("configure"); count != 7 {
+		t.Errorf("expected 7 commits, got %d", count)
+	}
+}
+
+func TestCancelledRequestLeavesBatch(t *testing.T) {
+	vc, _ := newTestConfig(t, true)
+
+	cancelled, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	var cancelledErr, setErr error
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		cancelledErr = vc.Set(cancelled, []string{"system", "domain-name"}, "example.com")
+	}()
+	go func() {
+		defer wg.Done()
+		setErr = vc.Set(context.Background(), []string{"system", "host-name"}, "router")
+	}()
+	time.Sleep(vc.batchWindow / 5)
+	cancel()
+	wg.Wait()
+
+	if !errors.Is(cancelledErr, context.Canceled) {
+		t.Errorf("expected the cancelled request to fail, got %v", cancelledErr)
+	}
+	if setErr != nil {
+		t.Errorf("unexpected error: %s", setErr)
+	}
+
+	ctx := context.Background()
+	if value, _ := vc.Show(ctx, []string{"system", "host-name"}); value != "router" {
+		t.Errorf("unexpected value: %v", value)
+	}
+	if value, _ := vc.Show(ctx, []string{"system", "domain-name"}); value != nil {
+		t.Errorf("expected the cancelled request not to be committed, got %v", value)
+	}
+}
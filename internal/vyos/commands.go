@@ -0,0 +1,132 @@
+package vyos
+
+import (
+	"fmt"
+)
+
+// ParseSetCommand parses a VyOS CLI `set` command, such as
+// `set interfaces ethernet eth0 address 10.0.0.1/24`, into the path it sets,
+// whose last component is the value. Components are quoted as in ParsePath.
+func ParseSetCommand(command string) ([]string, error) {
+	components, err := ParsePath(command)
+	if err != nil {
+		return nil, err
+	}
+	if len(components) == 0 || components[0] != "set" {
+		return nil, fmt.Errorf("command must start with set")
+	}
+	if len(components) < 3 {
+		return nil, fmt.Errorf("command must set a node and its value, such as `set system host-name vyos`")
+	}
+	return components[1:], nil
+}
+
+// CommandTree groups the paths of `set` commands into a configuration tree.
+// The CLI does not tell a value from a valueless node such as `disable`, so
+// the last component is a value unless other commands set nodes beneath it,
+// or current, the configuration on the router, holds a node at its parent.
+func CommandTree(paths [][]string, current any) map[string]any {
+	tree := map[string]any{}
+	for _, path := range paths {
+		addCommand(tree, path)
+	}
+	return shapeLike(tree, current).(map[string]any)
+}
+
+func addCommand(tree map[string]any, path []string) {
+	node := tree
+	for _, component := range path[:len(path)-2] {
+		node = childNode(node, component)
+	}
+
+	key, value := path[len(path)-2], path[len(path)-1]
+	switch existing := node[key].(type) {
+	case nil:
+		node[key] = value
+	case string:
+		if existing != value {
+			node[key] = []any{existing, value}
+		}
+	case []any:
+		for _, v := range existing {
+			if v == value {
+				return
+			}
+		}
+		node[key] = append(existing, value)
+	case map[string]any:
+		if _, ok := existing[value]; !ok {
+			existing[value] = map[string]any{}
+		}
+	}
+}
+
+// childNode returns the node under key, turning values set there into
+// valueless nodes as the key turned out to hold nodes.
+func childNode(node map[string]any, key string) map[string]any {
+	if child, ok := node[key].(map[string]any); ok {
+		return child
+	}
+
+	child := map[string]any{}
+	for _, leaf := range Leaves(node[key]) {
+		child[leaf.Value] = map[string]any{}
+	}
+	node[key] = child
+	return child
+}
+
+// shapeLike turns values of tree into valueless nodes where current holds
+// nodes instead of values.
+func shapeLike(tree any, current any) any {
+	currentTree, ok := current.(map[string]any)
+	if !ok {
+		return tree
+	}
+
+	switch v := tree.(type) {
+	case map[string]any:
+		for key, child := range v {
+			v[key] = shapeLike(child, currentTree[key])
+		}
+		return v
+	case string, []any:
+		result := map[string]any{}
+		for _, leaf := range Leaves(v) {
+			result[leaf.Value] = map[string]any{}
+		}
+		return result
+	}
+	return tree
+}
+
+// ContainsPath reports whether config holds the node or value a `set`
+// command path sets.
+func ContainsPath(config any, path []string) bool {
+	node := config
+	for i, component := range path {
+		switch v := node.(type) {
+		case map[string]any:
+			child, ok := v[component]
+			if !ok {
+				return false
+			}
+			node = child
+		case string:
+			return i == len(path)-1 && v == component
+		case []any:
+			if i != len(path)-1 {
+				return false
+			}
+			for _, value := range v {
+				if value == component {
+					return true
+				}
+			}
+			return false
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,103 @@
+package vyos
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseSetCommand(t *testing.T) {
+	path, err := ParseSetCommand(`set system login user vyos full-name 'VyOS User'`)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if expected := []string{"system", "login", "user", "vyos", "full-name", "VyOS User"}; !reflect.DeepEqual(path, expected) {
+		t.Errorf("unexpected path: %#v", path)
+	}
+
+	for _, command := range []string{"", "delete system host-name", "set system", "set system host-name 'vyos"} {
+		if _, err := ParseSetCommand(command); err == nil {
+			t.Errorf("%q: expected an error", command)
+		}
+	}
+}
+
+func parseSetCommands(t *testing.T, commands ...string) [][]string {
+	t.Helper()
+
+	var paths [][]string
+	for _, command := range commands {
+		path, err := ParseSetCommand(command)
+		if err != nil {
+			t.Fatalf("%q: unexpected error: %s", command, err)
+		}
+		paths = append(paths, path)
+	}
+	return paths
+}
+
+func TestCommandTree(t *testing.T) {
+	paths := parseSetCommands(t,
+		"set interfaces ethernet eth0 address 10.0.0.1/24",
+		"set interfaces ethernet eth0 address 10.0.1.1/24",
+		"set interfaces ethernet eth0 description WAN",
+		"set interfaces ethernet eth1 disable",
+		"set interfaces ethernet eth2 vif 10",
+		"set interfaces ethernet eth2 vif 10 description VLAN",
+	)
+
+	expected := decode(t, `{"interfaces":{"ethernet":{
+		"eth0":{"address":["10.0.0.1/24","10.0.1.1/24"],"description":"WAN"},
+		"eth1":"disable",
+		"eth2":{"vif":{"10":{"description":"VLAN"}}}
+	}}}`)
+	if tree := CommandTree(paths, nil); !reflect.DeepEqual(tree, expected) {
+		t.Errorf("unexpected tree: %#v", tree)
+	}
+
+	// eth1 holds nodes on the router, so disable is a valueless node.
+	current := decode(t, `{"interfaces":{"ethernet":{"eth1":{"hw-id":"00:53:00:00:00:01"}}}}`)
+	tree := CommandTree(paths, current)
+	if eth1 := tree["interfaces"].(map[string]any)["ethernet"].(map[string]any)["eth1"]; !reflect.DeepEqual(eth1, map[string]any{"disable": map[string]any{}}) {
+		t.Errorf("unexpected eth1: %#v", eth1)
+	}
+}
+
+func TestCommandTreeDiff(t *testing.T) {
+	current := decode(t, `{"interfaces":{"ethernet":{"eth0":{"address":["10.0.0.1/24","10.0.1.1/24"],"disable":{},"hw-id":"00:53:00:00:00:01"}}}}`)
+
+	old := CommandTree(parseSetCommands(t,
+		"set interfaces ethernet eth0 address 10.0.0.1/24",
+		"set interfaces ethernet eth0 address 10.0.1.1/24",
+		"set interfaces ethernet eth0 disable",
+	), current)
+	new := CommandTree(parseSetCommands(t,
+		"set interfaces ethernet eth0 address 10.0.0.1/24",
+	), current)
+
+	payload := Diff([]string{}, old, new, current)
+	expected := []map[string]any{
+		{"op": "delete", "path": []string{"interfaces", "ethernet", "eth0", "address"}, "value": "10.0.1.1/24"},
+		{"op": "delete", "path": []string{"interfaces", "ethernet", "eth0", "disable"}},
+	}
+	if !reflect.DeepEqual(payload, expected) {
+		t.Errorf("unexpected payload: %v", payload)
+	}
+}
+
+func TestContainsPath(t *testing.T) {
+	config := decode(t, `{"interfaces":{"ethernet":{"eth0":{"address":["10.0.0.1/24","10.0.1.1/24"],"description":"WAN","disable":{}}}}}`)
+
+	tests := map[string]bool{
+		"set interfaces ethernet eth0 address 10.0.1.1/24": true,
+		"set interfaces ethernet eth0 address 10.0.2.1/24": false,
+		"set interfaces ethernet eth0 description WAN":     true,
+		"set interfaces ethernet eth0 description LAN":     false,
+		"set interfaces ethernet eth0 disable":             true,
+		"set interfaces ethernet eth1 disable":             false,
+	}
+	for command, expected := range tests {
+		if contains := ContainsPath(config, parseSetCommands(t, command)[0]); contains != expected {
+			t.Errorf("%s: expected %v, got %v", command, expected, contains)
+		}
+	}
+}
@@ -2,27 +2,61 @@ package vyos
 
 import (
 	"context"
-	"errors"
-	"fmt"
-	"strings"
 	"sync"
-
-	"github.com/foltik/vyos-client-go/client"
+	"time"
 )
 
 type VyosConfig struct {
-	apiClient    *client.Client
+	backend      Backend
 	skipSaving   bool
 	saveFile     string
 	mutex        sync.Mutex
 	cachedConfig *map[string]any
+	batchMutex   sync.Mutex
+	batchWindow  time.Duration
+	pending      []*batchRequest
+
+	commitConfirmMinutes int64
+	confirmChecks        int
+	confirmCheckInterval time.Duration
+
+	maxRetries    int64
+	retryMaxWait  time.Duration
+	retryBaseWait time.Duration
+
+	// versionMutex is held while the version is detected, so it is only
+	// detected once.
+	versionMutex    sync.Mutex
+	version         Version
+	versionDetected bool
+}
+
+// Option configures optional behaviour of a VyosConfig.
+type Option func(*VyosConfig)
+
+// WithCommitConfirm commits using commit-confirm, so the router rolls back
+// any change after the given number of minutes unless the API still answers
+// after the commit. Zero disables commit-confirm.
+func WithCommitConfirm(minutes int64) Option {
+	return func(vc *VyosConfig) {
+		vc.commitConfirmMinutes = minutes
+	}
 }
 
-func New(apiClient *client.Client, skipSaving bool, saveFile string) *VyosConfig {
+func New(backend Backend, skipSaving bool, saveFile string, options ...Option) *VyosConfig {
 	config := &VyosConfig{
-		skipSaving: skipSaving,
-		saveFile:   saveFile,
-		apiClient:  apiClient,
+		skipSaving:           skipSaving,
+		saveFile:             saveFile,
+		backend:              backend,
+		batchWindow:          DefaultBatchWindow,
+		confirmChecks:        DefaultConfirmChecks,
+		confirmCheckInterval: DefaultConfirmCheckInterval,
+		maxRetries:           DefaultMaxRetries,
+		retryMaxWait:         DefaultRetryMaxWait,
+		retryBaseWait:        DefaultRetryBaseWait,
+	}
+	for _, option := range options {
+		option(config)
 	}
 	return config
 }
@@ -30,32 +64,23 @@ func New(apiClient *client.Client, skipSaving bool, saveFile string) *VyosConfig
 func (vc *VyosConfig) SaveIfRequired(ctx context.Context) error {
 	if vc.skipSaving {
 		return nil
-	} else if vc.saveFile == "" {
-		return vc.apiClient.Config.Save(ctx)
-	} else {
-		return vc.apiClient.Config.SaveFile(ctx, vc.saveFile)
 	}
-}
 
-func (vc *VyosConfig) getRemoteConfig(ctx context.Context) (map[string]any, error) {
-	resp, err := vc.apiClient.Request(ctx, "retrieve", map[string]any{
-		"op":   "showConfig",
-		"path": []string{},
+	return vc.retry(ctx, true, func() error {
+		return vc.backend.Save(ctx, vc.saveFile)
 	})
-	if err != nil {
-		if strings.Contains(err.Error(), "could not fetch config") {
-			// If we get an empty path error, consume it and return nil
-			return nil, nil
-		} else {
-			return nil, err
-		}
-	}
+}
 
-	obj, ok := resp.(map[string]any)
-	if !ok {
-		return nil, errors.New("received unexpected repsonse format from server")
+func (vc *VyosConfig) getRemoteConfig(ctx context.Context) (config map[string]any, err error) {
+	if err := vc.requireBackend(ctx); err != nil {
+		return nil, err
 	}
-	return obj, nil
+
+	err = vc.retry(ctx, true, func() error {
+		config, err = vc.backend.Retrieve(ctx)
+		return err
+	})
+	return config, err
 }
 
 func (vc *VyosConfig) GetFullConfig(ctx context.Context) (*map[string]any, error) {
@@ -78,18 +103,14 @@ func (vc *VyosConfig) invalidateConfigCache() {
 	vc.mutex.Unlock()
 }
 
-func (vc *VyosConfig) Show(ctx context.Context, path string) (any, error) {
-	path_components := strings.Split(path, " ")
-	if path == "" {
-		path_components = []string{}
-	}
-
+// Show returns the configuration under path, or nil if it does not exist.
+func (vc *VyosConfig) Show(ctx context.Context, path []string) (any, error) {
 	fullConfig, err := vc.GetFullConfig(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	config, err := getConfigFromPath(*fullConfig, path_components)
+	config, err := getConfigFromPath(*fullConfig, path)
 	if err != nil {
 		return nil, err
 	}
@@ -97,38 +118,26 @@ func (vc *VyosConfig) Show(ctx context.Context, path string) (any, error) {
 	return config, nil
 }
 
-func (vc *VyosConfig) Set(ctx context.Context, path string, value any) error {
-	vc.invalidateConfigCache()
-
-	err := vc.apiClient.Config.Set(ctx, path, value)
-	if err != nil {
-		return err
-	}
-
-	err = vc.SaveIfRequired(ctx)
-	if err != nil {
-		return err
-	}
-	return nil
-}
-
-func (vc *VyosConfig) Delete(ctx context.Context, path string) error {
-	vc.invalidateConfigCache()
-	err := vc.apiClient.Config.Delete(ctx, path)
-	if err != nil {
-		return err
+// Set sets every leaf of the configuration tree value under path.
+func (vc *VyosConfig) Set(ctx context.Context, path []string, value any) error {
+	payload := []map[string]any{}
+	for _, leaf := range Leaves(Normalize(value)) {
+		payload = append(payload, map[string]any{
+			"op":    "set",
+			"path":  joinPath(path, leaf.Path),
+			"value": leaf.Value,
+		})
 	}
 
-	err = vc.SaveIfRequired(ctx)
-	if err != nil {
-		return err
-	}
-
-	return nil
+	return vc.Configure(ctx, payload)
 }
 
-func (vc *VyosConfig) ApiRequest(ctx context.Context, endpoint string, payload any) (any, error) {
-	return vc.apiClient.Request(ctx, endpoint, payload)
+// Delete deletes path and everything beneath it.
+func (vc *VyosConfig) Delete(ctx context.Context, path []string) error {
+	return vc.Configure(ctx, []map[string]any{{
+		"op":   "delete",
+		"path": path,
+	}})
 }
 
 func getConfigFromPath(configTree map[string]interface{}, path_components []string) (rval interface{}, err error) {
@@ -143,7 +152,12 @@ func getConfigFromPath(configTree map[string]interface{}, path_components []stri
 	} else if len(path_components) == 1 { // we've reached the final path component
 		return rval, nil
 	} else if configTree, ok = rval.(map[string]interface{}); !ok {
-		return nil, fmt.Errorf("malformed configTree at %#v", rval)
+		// A leaf is addressed with its value as the last component, such as
+		// `system login banner pre-login 'Authorised access only'`.
+		if len(path_components) == 2 {
+			return Child(rval, path_components[1]), nil
+		}
+		return nil, nil
 	} else { // 1+ more path components
 		return getConfigFromPath(configTree, path_components[1:])
 	}
@@ -0,0 +1,343 @@
+package vyos
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// ConfigBoot is a configuration in the curly brace format of config.boot
+// files and `show configuration`:
+//
+//	interfaces {
+//	    ethernet eth0 {
+//	        address 192.0.2.10/24
+//	        address 2001:db8::10/64
+//	        description "WAN uplink"
+//	    }
+//	}
+//	// vyos-config-version: "interfaces@31:system@27"
+//	// Release version: 1.4.0
+type ConfigBoot struct {
+	// Tree is the configuration in the form returned by `showConfig`.
+	Tree map[string]any
+	// TagNodes holds the paths of tag nodes, formatted with FormatPath, such
+	// as `interfaces ethernet`. Their children are written on the same line,
+	// as `ethernet eth0 {`.
+	TagNodes map[string]bool
+	// Footer holds the comment lines after the configuration, which record
+	// the version of the configuration syntax.
+	Footer []string
+}
+
+// IsTagNode reports whether the node at path is a tag node.
+func (c *ConfigBoot) IsTagNode(path []string) bool {
+	return c.TagNodes[FormatPath(path)]
+}
+
+func (c *ConfigBoot) String() string {
+	return FormatConfigBoot(c.Tree, c.IsTagNode, c.Footer)
+}
+
+type configBootToken struct {
+	kind rune // 'w' for words, '{', '}' or '\n'
+	text string
+	line int
+}
+
+// ParseConfigBoot parses a configuration in the config.boot format. Comments
+// are ignored, except those after the configuration, which become the footer.
+func ParseConfigBoot(text string) (*ConfigBoot, error) {
+	tokens, footer, err := lexConfigBoot(text)
+	if err != nil {
+		return nil, err
+	}
+
+	config := &ConfigBoot{
+		Tree:     map[string]any{},
+		TagNodes: map[string]bool{},
+		Footer:   footer,
+	}
+
+	type frame struct {
+		tree map[string]any
+		path []string
+	}
+	stack := []frame{{config.Tree, []string{}}}
+
+	var words []configBootToken
+	for _, token := range tokens {
+		if token.kind == 'w' {
+			words = append(words, token)
+			continue
+		}
+
+		current := stack[len(stack)-1]
+
+		if token.kind == '{' {
+			var child map[string]any
+			var childPath []string
+			switch len(words) {
+			case 1:
+				child, err = configBootNode(current.tree, words[0].text)
+				childPath = joinPath(current.path, []string{words[0].text})
+			case 2:
+				var tag map[string]any
+				tag, err = configBootNode(current.tree, words[0].text)
+				if err == nil {
+					config.TagNodes[FormatPath(joinPath(current.path, []string{words[0].text}))] = true
+					child, err = configBootNode(tag, words[1].text)
+				}
+				childPath = joinPath(current.path, []string{words[0].text, words[1].text})
+			default:
+				err = fmt.Errorf("line %d: expected a node name before {", token.line)
+			}
+			if err != nil {
+				return nil, configBootError(token, err)
+			}
+
+			stack = append(stack, frame{child, childPath})
+			words = nil
+			continue
+		}
+
+		// A newline or closing brace ends a leaf.
+		if len(words) > 0 {
+			if err := configBootLeafOrNode(current.tree, words); err != nil {
+				return nil, err
+			}
+			words = nil
+		}
+
+		if token.kind == '}' {
+			if len(stack) == 1 {
+				return nil, fmt.Errorf("line %d: unexpected }", token.line)
+			}
+			stack = stack[:len(stack)-1]
+		}
+	}
+
+	if len(words) > 0 {
+		if err := configBootLeafOrNode(stack[len(stack)-1].tree, words); err != nil {
+			return nil, err
+		}
+	}
+	if len(stack) > 1 {
+		return nil, fmt.Errorf("missing } closing %s", FormatPath(stack[len(stack)-1].path))
+	}
+
+	return config, nil
+}
+
+func configBootLeafOrNode(tree map[string]any, words []configBootToken) error {
+	switch len(words) {
+	case 1:
+		if _, exists := tree[words[0].text]; !exists {
+			tree[words[0].text] = map[string]any{}
+		}
+		return nil
+	case 2:
+		return configBootError(words[0], configBootLeaf(tree, words[0].text, words[1].text))
+	}
+	return fmt.Errorf("line %d: unexpected %q after the value of %s", words[2].line, words[2].text, words[0].text)
+}
+
+func configBootError(token configBootToken, err error) error {
+	if err == nil || strings.HasPrefix(err.Error(), "line ") {
+		return err
+	}
+	return fmt.Errorf("line %d: %w", token.line, err)
+}
+
+// configBootNode returns the node name under tree, creating it if needed.
+func configBootNode(tree map[string]any, name string) (map[string]any, error) {
+	switch existing := tree[name].(type) {
+	case nil:
+		child := map[string]any{}
+		tree[name] = child
+		return child, nil
+	case map[string]any:
+		return existing, nil
+	}
+	return nil, fmt.Errorf("%s is a leaf and cannot hold nodes", name)
+}
+
+// configBootLeaf adds a value to the leaf name under tree, turning repeated
+// leaves into a leaf list.
+func configBootLeaf(tree map[string]any, name string, value string) error {
+	switch existing := tree[name].(type) {
+	case nil:
+		tree[name] = value
+	case string:
+		tree[name] = []any{existing, value}
+	case []any:
+		tree[name] = append(existing, value)
+	default:
+		return fmt.Errorf("%s is a node and cannot hold a value", name)
+	}
+	return nil
+}
+
+// lexConfigBoot splits text into words, braces and newlines, returning the
+// comments after the last node separately.
+func lexConfigBoot(text string) ([]configBootToken, []string, error) {
+	var tokens []configBootToken
+	var footer []string
+	depth := 0
+	line := 1
+
+	for i := 0; i < len(text); {
+		c := text[i]
+		switch {
+		case c == '\n':
+			tokens = append(tokens, configBootToken{kind: '\n', line: line})
+			line++
+			i++
+
+		case c == ' ' || c == '\t' || c == '\r':
+			i++
+
+		case c == '{' || c == '}':
+			if c == '{' {
+				depth++
+			} else {
+				depth--
+			}
+			tokens = append(tokens, configBootToken{kind: rune(c), line: line})
+			i++
+
+		case strings.HasPrefix(text[i:], "//") || strings.HasPrefix(text[i:], "/*"):
+			end := strings.IndexByte(text[i:], '\n')
+			if text[i+1] == '*' {
+				end = strings.Index(text[i+2:], "*/")
+				if end < 0 {
+					return nil, nil, fmt.Errorf("line %d: unterminated comment", line)
+				}
+				end += 4
+			} else if end < 0 {
+				end = len(text) - i
+			}
+			comment := text[i : i+end]
+			line += strings.Count(comment, "\n")
+			i += end
+
+			// Comments before or inside nodes are dropped, the ones after the
+			// configuration are kept as the footer.
+			if depth == 0 {
+				footer = append(footer, comment)
+			}
+
+		case c == '"' || c == '\'':
+			var value strings.Builder
+			startLine := line
+			i++
+			for ; i < len(text) && text[i] != c; i++ {
+				if text[i] == '\\' && c == '"' && i+1 < len(text) {
+					i++
+				}
+				if text[i] == '\n' {
+					line++
+				}
+				value.WriteByte(text[i])
+			}
+			if i >= len(text) {
+				return nil, nil, fmt.Errorf("line %d: unterminated %c quote", startLine, c)
+			}
+			i++
+			tokens = append(tokens, configBootToken{kind: 'w', text: value.String(), line: startLine})
+
+		default:
+			start := i
+			for i < len(text) && !strings.ContainsRune(" \t\r\n{}", rune(text[i])) {
+				i++
+			}
+			tokens = append(tokens, configBootToken{kind: 'w', text: text[start:i], line: line})
+
+			// A node after a comment means it was not part of the footer.
+			if depth == 0 {
+				footer = nil
+			}
+		}
+	}
+
+	return tokens, footer, nil
+}
+
+// FormatConfigBoot writes tree in the config.boot format, with nodes sorted
+// by name and leaf lists in their order, duplicates dropped; list order is
+// significant for leaves such as name-server. isTagNode tells which nodes are tag
+// nodes; when it is nil every node is written as a plain node, which VyOS
+// reads back to the same tree.
+func FormatConfigBoot(tree map[string]any, isTagNode func(path []string) bool, footer []string) string {
+	if isTagNode == nil {
+		isTagNode = func([]string) bool { return false }
+	}
+
+	var builder strings.Builder
+	normalized, _ := Normalize(tree).(map[string]any)
+	formatConfigBootNode(&builder, normalized, []string{}, "", isTagNode)
+
+	for _, comment := range footer {
+		builder.WriteString(comment)
+		builder.WriteString("\n")
+	}
+	return builder.String()
+}
+
+func formatConfigBootNode(builder *strings.Builder, tree map[string]any, path []string, indent string, isTagNode func([]string) bool) {
+	keys := make([]string, 0, len(tree))
+	for key := range tree {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		childPath := joinPath(path, []string{key})
+		name := quoteConfigBoot(key)
+
+		switch child := tree[key].(type) {
+		case string:
+			fmt.Fprintf(builder, "%s%s %s\n", indent, name, quoteConfigBoot(child))
+		case []any:
+			for _, value := range child {
+				fmt.Fprintf(builder, "%s%s %s\n", indent, name, quoteConfigBoot(fmt.Sprint(value)))
+			}
+		case map[string]any:
+			if isTagNode(childPath) {
+				tags := make([]string, 0, len(child))
+				for tag := range child {
+					tags = append(tags, tag)
+				}
+				sort.Strings(tags)
+
+				for _, tag := range tags {
+					fmt.Fprintf(builder, "%s%s %s {\n", indent, name, quoteConfigBoot(tag))
+					tagTree, _ := child[tag].(map[string]any)
+					formatConfigBootNode(builder, tagTree, joinPath(childPath, []string{tag}), indent+"    ", isTagNode)
+					fmt.Fprintf(builder, "%s}\n", indent)
+				}
+			} else if len(child) == 0 {
+				fmt.Fprintf(builder, "%s%s\n", indent, name)
+			} else {
+				fmt.Fprintf(builder, "%s%s {\n", indent, name)
+				formatConfigBootNode(builder, child, childPath, indent+"    ", isTagNode)
+				fmt.Fprintf(builder, "%s}\n", indent)
+			}
+		}
+	}
+}
+
+// quoteConfigBoot quotes a name or value when it would not read back as a
+// single word.
+func quoteConfigBoot(value string) string {
+	if value != "" && !strings.HasPrefix(value, "/*") && !strings.HasPrefix(value, "//") &&
+		strings.IndexFunc(value, func(r rune) bool {
+			return unicode.IsSpace(r) || strings.ContainsRune(`{}"'\`, r)
+		}) < 0 {
+		return value
+	}
+
+	replacer := strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+	return `"` + replacer.Replace(value) + `"`
+}
@@ -0,0 +1,153 @@
+package vyos
+
+import (
+	"reflect"
+	"testing"
+)
+
+const testConfigBoot = `/* Managed by Terraform */
+interfaces {
+    ethernet eth0 {
+        address 192.0.2.10/24
+        address 2001:db8::10/64
+        description "WAN \"uplink\""
+        hw-id 00:53:00:00:00:01
+    }
+    ethernet eth1 {
+        disable
+    }
+    loopback lo {
+    }
+}
+system {
+    /* Changed by ops */
+    host-name vyos
+    login {
+        banner {
+            pre-login "Authorised access only"
+        }
+    }
+    name-server "1.1.1.1"
+}
+// Warning: Do not remove the following line.
+// vyos-config-version: "interfaces@31:system@27"
+// Release version: 1.4.0
+`
+
+func TestParseConfigBoot(t *testing.T) {
+	config, err := ParseConfigBoot(testConfigBoot)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	expected := decode(t, `{
+		"interfaces": {
+			"ethernet": {
+				"eth0": {
+					"address": ["192.0.2.10/24", "2001:db8::10/64"],
+					"description": "WAN \"uplink\"",
+					"hw-id": "00:53:00:00:00:01"
+				},
+				"eth1": {"disable": {}}
+			},
+			"loopback": {"lo": {}}
+		},
+		"system": {
+			"host-name": "vyos",
+			"login": {"banner": {"pre-login": "Authorised access only"}},
+			"name-server": "1.1.1.1"
+		}
+	}`)
+	if !reflect.DeepEqual(config.Tree, expected) {
+		t.Errorf("unexpected tree: %#v", config.Tree)
+	}
+
+	if !config.IsTagNode([]string{"interfaces", "ethernet"}) || config.IsTagNode([]string{"system", "login"}) {
+		t.Errorf("unexpected tag nodes: %v", config.TagNodes)
+	}
+
+	expectedFooter := []string{
+		"// Warning: Do not remove the following line.",
+		`// vyos-config-version: "interfaces@31:system@27"`,
+		"// Release version: 1.4.0",
+	}
+	if !reflect.DeepEqual(config.Footer, expectedFooter) {
+		t.Errorf("unexpected footer: %#v", config.Footer)
+	}
+}
+
+func TestFormatConfigBoot(t *testing.T) {
+	config, err := ParseConfigBoot(testConfigBoot)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	expected := `interfaces {
+    ethernet eth0 {
+        address 192.0.2.10/24
+        address 2001:db8::10/64
+        description "WAN \"uplink\""
+        hw-id 00:53:00:00:00:01
+    }
+    ethernet eth1 {
+        disable
+    }
+    loopback lo {
+    }
+}
+system {
+    host-name vyos
+    login {
+        banner {
+            pre-login "Authorised access only"
+        }
+    }
+    name-server 1.1.1.1
+}
+// Warning: Do not remove the following line.
+// vyos-config-version: "interfaces@31:system@27"
+// Release version: 1.4.0
+`
+	if text := config.String(); text != expected {
+		t.Errorf("unexpected config.boot:\n%s", text)
+	}
+
+	// Without tag nodes, the plain nodes read back to the same tree.
+	plain, err := ParseConfigBoot(FormatConfigBoot(config.Tree, nil, nil))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !reflect.DeepEqual(plain.Tree, config.Tree) {
+		t.Errorf("unexpected tree: %#v", plain.Tree)
+	}
+
+	// Leaf lists keep their order.
+	tree := map[string]any{
+		"system": map[string]any{"name-server": []any{"9.9.9.9", "1.1.1.1", "9.9.9.9"}},
+	}
+	expected = `system {
+    name-server 9.9.9.9
+    name-server 1.1.1.1
+}
+`
+	if text := FormatConfigBoot(tree, nil, nil); text != expected {
+		t.Errorf("unexpected config.boot:\n%s", text)
+	}
+}
+
+func TestParseConfigBootErrors(t *testing.T) {
+	tests := map[string]string{
+		"unclosed node":   "system {\n    host-name vyos\n",
+		"extra brace":     "system {\n}\n}\n",
+		"extra words":     "system {\n    host-name vyos router\n}\n",
+		"unclosed quote":  "system {\n    host-name \"vyos\n}\n",
+		"value and node":  "system {\n    login vyos\n    login {\n    }\n}\n",
+		"unended comment": "/* system {\n}\n",
+	}
+
+	for name, text := range tests {
+		if _, err := ParseConfigBoot(text); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
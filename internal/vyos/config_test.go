@@ -0,0 +1,79 @@
+package vyos
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/TGNThump/terraform-provider-vyos/internal/vyostest"
+)
+
+func newTestConfig(t *testing.T, skipSaving bool) (*VyosConfig, *vyostest.Server) {
+	server := vyostest.NewServer("key")
+	t.Cleanup(server.Close)
+	vc := New(NewApiBackend(http.DefaultClient, server.URL, "key"), skipSaving, "")
+	vc.retryBaseWait = time.Millisecond
+	return vc, server
+}
+
+func TestShowUsesCachedConfig(t *testing.T) {
+	ctx := context.Background()
+	vc, server := newTestConfig(t, true)
+	server.SetConfig(map[string]any{
+		"system": map[string]any{"host-name": "vyos"},
+	})
+
+	for i := 0; i < 3; i++ {
+		value, err := vc.Show(ctx, []string{"system", "host-name"})
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if value != "vyos" {
+			t.Errorf("unexpected value: %v", value)
+		}
+	}
+
+	if count := server.RequestCount("retrieve"); count != 1 {
+		t.Errorf("expected a single retrieve, got %d", count)
+	}
+
+	missing, err := vc.Show(ctx, []string{"system", "domain-name"})
+	if err != nil || missing != nil {
+		t.Errorf("expected missing path to be nil, got %v, %v", missing, err)
+	}
+}
+
+func TestSetInvalidatesCacheAndSaves(t *testing.T) {
+	ctx := context.Background()
+	vc, server := newTestConfig(t, false)
+
+	if _, err := vc.Show(ctx, []string{}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	err := vc.Set(ctx, []string{"firewall", "name", "TEST"}, map[string]any{"default-action": "drop"})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	value, err := vc.Show(ctx, []string{"firewall", "name", "TEST"})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if expected := map[string]any{"default-action": "drop"}; !reflect.DeepEqual(value, expected) {
+		t.Errorf("unexpected value: %v, expected %v", value, expected)
+	}
+
+	if saves := server.Saves(); len(saves) != 1 {
+		t.Errorf("expected one save, got %v", saves)
+	}
+
+	if err := vc.Delete(ctx, []string{"firewall", "name", "TEST"}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if value, _ := vc.Show(ctx, []string{"firewall", "name", "TEST"}); value != nil {
+		t.Errorf("expected deleted path to be nil, got %v", value)
+	}
+}
@@ -0,0 +1,75 @@
+package vyos
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+const (
+	// DefaultConfirmChecks is how many times the API is checked after a
+	// commit-confirm before giving up and letting the router roll back.
+	DefaultConfirmChecks = 3
+	// DefaultConfirmCheckInterval is the wait between those checks.
+	DefaultConfirmCheckInterval = 5 * time.Second
+)
+
+// ErrCommitNotConfirmed is returned when the API stopped answering after a
+// commit-confirm, leaving the router to roll the change back.
+var ErrCommitNotConfirmed = errors.New("the VyOS API stopped answering after the commit")
+
+// commit sends a list of `configure` operations to the router. With
+// commit-confirm enabled the commit is only confirmed once the API has been
+// seen answering after the change.
+func (vc *VyosConfig) commit(ctx context.Context, payload []map[string]any) error {
+	if err := vc.requireBackend(ctx); err != nil {
+		return err
+	}
+
+	if vc.commitConfirmMinutes > 0 {
+		if err := vc.Version(ctx).Require(FeatureCommitConfirm); err != nil {
+			return fmt.Errorf("changes cannot be committed with commit-confirm: %w", err)
+		}
+	}
+
+	err := vc.retry(ctx, false, func() error {
+		return vc.backend.Configure(ctx, payload, vc.commitConfirmMinutes)
+	})
+	if err != nil || vc.commitConfirmMinutes <= 0 {
+		return err
+	}
+
+	if err := vc.checkReachable(ctx); err != nil {
+		return fmt.Errorf("%w, the router will roll back the change in %d minutes: %s", ErrCommitNotConfirmed, vc.commitConfirmMinutes, err)
+	}
+
+	tflog.Info(ctx, "VyOS API answered after the commit, confirming it")
+
+	return vc.retry(ctx, false, func() error {
+		return vc.backend.Confirm(ctx)
+	})
+}
+
+func (vc *VyosConfig) checkReachable(ctx context.Context) error {
+	var err error
+	for i := 0; i < vc.confirmChecks; i++ {
+		if i > 0 {
+			select {
+			case <-time.After(vc.confirmCheckInterval):
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+
+		// Checks are already repeated, so they are not retried on their own.
+		err = vc.backend.Ping(ctx)
+		if err == nil {
+			return nil
+		}
+		tflog.Warn(ctx, "VyOS API did not answer after commit: "+err.Error())
+	}
+	return err
+}
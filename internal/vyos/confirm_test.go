@@ -0,0 +1,74 @@
+package vyos
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCommitConfirmIsConfirmed(t *testing.T) {
+	ctx := context.Background()
+	vc, server := newTestConfig(t, false)
+	WithCommitConfirm(5)(vc)
+
+	if err := vc.Set(ctx, []string{"system", "host-name"}, "router"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if server.PendingConfirm() {
+		t.Error("expected the commit to be confirmed")
+	}
+	if saves := server.Saves(); len(saves) != 1 {
+		t.Errorf("expected one save, got %v", saves)
+	}
+}
+
+func TestCommitConfirmRollsBackWhenUnreachable(t *testing.T) {
+	ctx := context.Background()
+	vc, server := newTestConfig(t, false)
+	WithCommitConfirm(5)(vc)
+	vc.confirmChecks = 2
+	vc.confirmCheckInterval = 10 * time.Millisecond
+
+	server.SetConfig(map[string]any{
+		"system": map[string]any{"host-name": "vyos"},
+	})
+	server.CutOffAfterNextCommit()
+
+	err := vc.Set(ctx, []string{"system", "host-name"}, "router")
+	if !errors.Is(err, ErrCommitNotConfirmed) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !server.PendingConfirm() {
+		t.Error("expected the commit to be left unconfirmed")
+	}
+	if saves := server.Saves(); len(saves) != 0 {
+		t.Errorf("expected an unconfirmed commit not to be saved, got %v", saves)
+	}
+
+	server.ExpireConfirm()
+
+	if value, err := vc.Show(ctx, []string{"system", "host-name"}); err != nil || value != "vyos" {
+		t.Errorf("expected the change to be rolled back, got %v, %v", value, err)
+	}
+}
+
+func TestCommitConfirmChecksNewConnections(t *testing.T) {
+	ctx := context.Background()
+	vc, server := newTestConfig(t, false)
+	WithCommitConfirm(5)(vc)
+	vc.confirmChecks = 2
+	vc.confirmCheckInterval = 10 * time.Millisecond
+
+	server.RefuseNewConnectionsAfterNextCommit()
+
+	err := vc.Set(ctx, []string{"system", "host-name"}, "router")
+	if !errors.Is(err, ErrCommitNotConfirmed) {
+		t.Fatalf("expected a change refusing new connections not to be confirmed, got %v", err)
+	}
+	if !server.PendingConfirm() {
+		t.Error("expected the commit to be left unconfirmed")
+	}
+}
@@ -0,0 +1,235 @@
+package vyos
+
+import (
+	"sort"
+	"strings"
+)
+
+// Leaf is a single value in a configuration tree, the unit VyOS sets and
+// deletes. Valueless nodes have an empty Value.
+type Leaf struct {
+	Path  []string
+	Value string
+}
+
+func (l Leaf) key() string {
+	return strings.Join(append(append([]string{}, l.Path...), l.Value), "\x00")
+}
+
+func (l Leaf) pathKey() string {
+	return strings.Join(l.Path, "\x00")
+}
+
+// Leaves flattens a normalized configuration tree into its leaves, sorted by
+// path so the result is stable. The values of a leaf list keep their order.
+func Leaves(tree any) []Leaf {
+	var leaves []Leaf
+	collectLeaves(&leaves, tree, []string{})
+	sort.SliceStable(leaves, func(i, j int) bool {
+		return leaves[i].pathKey() < leaves[j].pathKey()
+	})
+	return leaves
+}
+
+func collectLeaves(leaves *[]Leaf, tree any, path []string) {
+	switch v := tree.(type) {
+	case map[string]any:
+		if len(v) == 0 {
+			*leaves = append(*leaves, Leaf{path, ""})
+		}
+		for key, child := range v {
+			collectLeaves(leaves, child, append(append([]string{}, path...), key))
+		}
+	case []any:
+		for _, child := range v {
+			collectLeaves(leaves, child, path)
+		}
+	case string:
+		*leaves = append(*leaves, Leaf{path, v})
+	}
+}
+
+// Diff computes the `configure` operations which turn old into new under
+// path, touching only the leaves which changed. current is the configuration
+// on the router under path, which may hold nodes managed elsewhere; a delete
+// that would leave one of its nodes empty deletes the node instead.
+func Diff(path []string, old any, new any, current any) []map[string]any {
+	var oldLeaves, newLeaves []Leaf
+	if old != nil {
+		oldLeaves = Leaves(Normalize(old))
+	}
+	if new != nil {
+		newLeaves = Leaves(Normalize(new))
+	}
+
+	// A reordered leaf list is set again from scratch, as VyOS appends the
+	// values it sets.
+	reordered := reorderedLists(oldLeaves, newLeaves)
+
+	oldKeys := map[string]bool{}
+	for _, leaf := range oldLeaves {
+		if !reordered[leaf.pathKey()] {
+			oldKeys[leaf.key()] = true
+		}
+	}
+	newKeys := map[string]bool{}
+	for _, leaf := range newLeaves {
+		if !reordered[leaf.pathKey()] {
+			newKeys[leaf.key()] = true
+		}
+	}
+
+	d := &differ{
+		removed: map[string]bool{},
+		old:     oldLeaves,
+		new:     newLeaves,
+	}
+	for _, leaf := range oldLeaves {
+		if !newKeys[leaf.key()] {
+			d.removed[leaf.key()] = true
+		}
+	}
+
+	var payload []map[string]any
+
+	if current != nil {
+		current = Normalize(current)
+	}
+	for _, leaf := range d.deletes(current, []string{}) {
+		op := map[string]any{
+			"op":   "delete",
+			"path": joinPath(path, leaf.Path),
+		}
+		if leaf.Value != "" {
+			op["value"] = leaf.Value
+		}
+		payload = append(payload, op)
+	}
+
+	for _, leaf := range newLeaves {
+		if oldKeys[leaf.key()] {
+			continue
+		}
+		payload = append(payload, map[string]any{
+			"op":    "set",
+			"path":  joinPath(path, leaf.Path),
+			"value": leaf.Value,
+		})
+	}
+
+	return payload
+}
+
+// reorderedLists returns the paths of the ordered leaf lists whose values
+// cannot reach their new order by removing values and appending new ones.
+func reorderedLists(oldLeaves []Leaf, newLeaves []Leaf) map[string]bool {
+	oldValues := map[string][]string{}
+	for _, leaf := range oldLeaves {
+		oldValues[leaf.pathKey()] = append(oldValues[leaf.pathKey()], leaf.Value)
+	}
+	newValues := map[string][]string{}
+	for _, leaf := range newLeaves {
+		newValues[leaf.pathKey()] = append(newValues[leaf.pathKey()], leaf.Value)
+	}
+
+	reordered := map[string]bool{}
+	for _, leaf := range newLeaves {
+		key := leaf.pathKey()
+		if reordered[key] || len(leaf.Path) == 0 || Unordered(leaf.Path[len(leaf.Path)-1]) {
+			continue
+		}
+
+		added := map[string]bool{}
+		for _, value := range newValues[key] {
+			added[value] = true
+		}
+		var kept []string
+		for _, value := range oldValues[key] {
+			if added[value] {
+				kept = append(kept, value)
+			}
+		}
+		for i, value := range kept {
+			if newValues[key][i] != value {
+				reordered[key] = true
+				break
+			}
+		}
+	}
+	return reordered
+}
+
+type differ struct {
+	removed map[string]bool
+	old     []Leaf
+	new     []Leaf
+}
+
+// deletes returns the deletes needed to remove the removed leaves below path,
+// deleting a whole node of current instead when every leaf beneath it is
+// removed and nothing is set beneath it again.
+func (d *differ) deletes(current any, path []string) []Leaf {
+	tree, ok := current.(map[string]any)
+	if !ok || len(tree) == 0 {
+		// A leaf or valueless node, delete the removed values still present.
+		present := map[string]bool{}
+		for _, leaf := range Leaves(current) {
+			present[Leaf{path, leaf.Value}.key()] = true
+		}
+
+		var deletes []Leaf
+		for _, leaf := range d.old {
+			if d.removed[leaf.key()] && present[leaf.key()] {
+				deletes = append(deletes, leaf)
+			}
+		}
+		return deletes
+	}
+
+	if d.removesAll(tree, path) {
+		return []Leaf{{path, ""}}
+	}
+
+	keys := make([]string, 0, len(tree))
+	for key := range tree {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var deletes []Leaf
+	for _, key := range keys {
+		deletes = append(deletes, d.deletes(tree[key], joinPath(path, []string{key}))...)
+	}
+
+	return deletes
+}
+
+func (d *differ) removesAll(tree map[string]any, path []string) bool {
+	for _, leaf := range d.new {
+		if hasPrefix(leaf.Path, path) {
+			return false
+		}
+	}
+	for _, leaf := range Leaves(tree) {
+		if !d.removed[Leaf{joinPath(path, leaf.Path), leaf.Value}.key()] {
+			return false
+		}
+	}
+	return true
+}
+
+func hasPrefix(path []string, prefix []string) bool {
+	if len(prefix) > len(path) {
+		return false
+	}
+	for i := range prefix {
+		if path[i] != prefix[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func joinPath(path []string, subpath []string) []string {
+	return append(append([]string{}, path...), subpath...)
+}
@@ -0,0 +1,127 @@
+package vyos
+
+import (
+	"reflect"
+	"testing"
+)
+
+func checkDiff(t *testing.T, old string, new string, current string, expected []map[string]any) {
+	t.Helper()
+
+	var oldTree, newTree, currentTree any
+	if old != "" {
+		oldTree = decode(t, old)
+	}
+	if new != "" {
+		newTree = decode(t, new)
+	}
+	if current != "" {
+		currentTree = decode(t, current)
+	}
+
+	payload := Diff([]string{"firewall", "name", "TEST"}, oldTree, newTree, currentTree)
+	if !reflect.DeepEqual(payload, expected) {
+		t.Errorf("unexpected payload: %v, expected: %v", payload, expected)
+	}
+}
+
+func TestDiffChangedLeaf(t *testing.T) {
+	checkDiff(t,
+		`{"default-action":"drop","description":"old"}`,
+		`{"default-action":"drop","description":"new"}`,
+		`{"default-action":"drop","description":"old"}`,
+		[]map[string]any{
+			{"op": "delete", "path": []string{"firewall", "name", "TEST", "description"}, "value": "old"},
+			{"op": "set", "path": []string{"firewall", "name", "TEST", "description"}, "value": "new"},
+		},
+	)
+}
+
+func TestDiffRemovedNode(t *testing.T) {
+	checkDiff(t,
+		`{"rule":{"10":{"action":"accept","protocol":"tcp"},"20":{"action":"drop"}}}`,
+		`{"rule":{"20":{"action":"drop"}}}`,
+		`{"rule":{"10":{"action":"accept","protocol":"tcp"},"20":{"action":"drop"}}}`,
+		[]map[string]any{
+			{"op": "delete", "path": []string{"firewall", "name", "TEST", "rule", "10"}},
+		},
+	)
+}
+
+func TestDiffLeavesUnmanagedNodes(t *testing.T) {
+	checkDiff(t,
+		`{"rule":{"10":{"action":"accept"}}}`,
+		"",
+		`{"default-action":"drop","rule":{"10":{"action":"accept"},"20":{"action":"drop"}}}`,
+		[]map[string]any{
+			{"op": "delete", "path": []string{"firewall", "name", "TEST", "rule", "10"}},
+		},
+	)
+}
+
+func TestDiffLeafList(t *testing.T) {
+	checkDiff(t,
+		`{"address":["10.0.0.1","10.0.0.2"]}`,
+		`{"address":["10.0.0.2","10.0.0.3"]}`,
+		`{"address":["10.0.0.1","10.0.0.2","10.0.0.9"]}`,
+		[]map[string]any{
+			{"op": "delete", "path": []string{"firewall", "name", "TEST", "address"}, "value": "10.0.0.1"},
+			{"op": "set", "path": []string{"firewall", "name", "TEST", "address"}, "value": "10.0.0.3"},
+		},
+	)
+}
+
+func TestDiffDeleteEverything(t *testing.T) {
+	checkDiff(t,
+		`{"default-action":"drop"}`,
+		"",
+		`{"default-action":"drop"}`,
+		[]map[string]any{
+			{"op": "delete", "path": []string{"firewall", "name", "TEST"}},
+		},
+	)
+}
+
+func TestDiffSkipsMissingNodes(t *testing.T) {
+	checkDiff(t,
+		`{"default-action":"drop","description":"gone"}`,
+		`{"default-action":"drop"}`,
+		`{"default-action":"drop"}`,
+		nil,
+	)
+}
+
+func TestDiffReorderedLeafList(t *testing.T) {
+	checkDiff(t,
+		`{"name-server":["192.0.2.1","192.0.2.2"]}`,
+		`{"name-server":["192.0.2.2","192.0.2.1"]}`,
+		`{"name-server":["192.0.2.1","192.0.2.2"]}`,
+		[]map[string]any{
+			{"op": "delete", "path": []string{"firewall", "name", "TEST", "name-server"}, "value": "192.0.2.1"},
+			{"op": "delete", "path": []string{"firewall", "name", "TEST", "name-server"}, "value": "192.0.2.2"},
+			{"op": "set", "path": []string{"firewall", "name", "TEST", "name-server"}, "value": "192.0.2.2"},
+			{"op": "set", "path": []string{"firewall", "name", "TEST", "name-server"}, "value": "192.0.2.1"},
+		},
+	)
+}
+
+func TestDiffAppendedLeafListValue(t *testing.T) {
+	checkDiff(t,
+		`{"name-server":["192.0.2.1","192.0.2.2"]}`,
+		`{"name-server":["192.0.2.2","192.0.2.3"]}`,
+		`{"name-server":["192.0.2.1","192.0.2.2"]}`,
+		[]map[string]any{
+			{"op": "delete", "path": []string{"firewall", "name", "TEST", "name-server"}, "value": "192.0.2.1"},
+			{"op": "set", "path": []string{"firewall", "name", "TEST", "name-server"}, "value": "192.0.2.3"},
+		},
+	)
+}
+
+func TestDiffUnorderedLeafList(t *testing.T) {
+	checkDiff(t,
+		`{"address":["192.0.2.1","192.0.2.2"]}`,
+		`{"address":["192.0.2.2","192.0.2.1"]}`,
+		`{"address":["192.0.2.1","192.0.2.2"]}`,
+		nil,
+	)
+}
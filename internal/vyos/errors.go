@@ -0,0 +1,208 @@
+package vyos
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// ErrorKind classifies the errors returned while talking to the router.
+type ErrorKind int
+
+const (
+	// ErrorUnknown is an error the API returned that fits no other kind.
+	ErrorUnknown ErrorKind = iota
+	// ErrorAuth is an API key or SSH credentials the router does not accept,
+	// or an SSH host key which does not match.
+	ErrorAuth
+	// ErrorConnectivity is a router that could not be reached or did not
+	// answer in time.
+	ErrorConnectivity
+	// ErrorCommitValidation is a change the router refused to set or commit.
+	ErrorCommitValidation
+	// ErrorLockContention is a configuration locked by another session or
+	// commit.
+	ErrorLockContention
+	// ErrorMalformedResponse is an answer which is not a VyOS API response.
+	ErrorMalformedResponse
+	// ErrorTLS is a router certificate which could not be verified, or an
+	// endpoint which does not speak TLS.
+	ErrorTLS
+	// ErrorServer is an HTTP 5xx answer, such as an API daemon which is
+	// restarting or failed while handling the request.
+	ErrorServer
+)
+
+func (k ErrorKind) String() string {
+	switch k {
+	case ErrorAuth:
+		return "auth"
+	case ErrorConnectivity:
+		return "connectivity"
+	case ErrorCommitValidation:
+		return "commit-validation"
+	case ErrorLockContention:
+		return "lock-contention"
+	case ErrorMalformedResponse:
+		return "malformed-response"
+	case ErrorTLS:
+		return "tls"
+	case ErrorServer:
+		return "server-error"
+	default:
+		return "unknown"
+	}
+}
+
+// Error is an error returned while talking to the router, along with its
+// kind.
+type Error struct {
+	Kind ErrorKind
+	// Path is the configuration path the router rejected, for commit
+	// validation errors where it is known.
+	Path []string
+	// StatusCode is the HTTP status of the answer, zero when there was none.
+	StatusCode int
+	// SSH is set for errors of the SSH transport, rather than the HTTP API.
+	SSH bool
+	Err error
+}
+
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// KindOf returns the kind of err, or ErrorUnknown if it was not classified.
+func KindOf(err error) ErrorKind {
+	var vyosErr *Error
+	if errors.As(err, &vyosErr) {
+		return vyosErr.Kind
+	}
+	return ErrorUnknown
+}
+
+// errMalformedResponse is returned when a response holds data of the wrong
+// type.
+var errMalformedResponse = &Error{
+	Kind: ErrorMalformedResponse,
+	Err:  errors.New("received unexpected repsonse format from server"),
+}
+
+// errorPathPattern matches the `[ interfaces ethernet eth0 ]` and
+// `[[interfaces ethernet eth0]]` markers VyOS puts in front of the messages of
+// the node which failed.
+var errorPathPattern = regexp.MustCompile(`\[\[?\s*([^\[\]\n]+?)\s*\]\]?`)
+
+// lockMessages are the messages VyOS answers with while another session holds
+// the configuration lock.
+var lockMessages = []string{
+	"configuration is locked",
+	"config is locked",
+	"commit in progress",
+	"commit is in progress",
+	"another commit",
+	"lock held",
+}
+
+// authMessages are the messages of credentials the router does not accept.
+var authMessages = []string{
+	"api key",
+	"unable to authenticate",
+	"host key mismatch",
+}
+
+// classify wraps an error from a request to the router in an Error of the
+// matching kind. changes holds the operations of requests which change the
+// configuration, nil for requests which do not.
+func classify(err error, changes []map[string]any) error {
+	if err == nil || KindOf(err) != ErrorUnknown {
+		return err
+	}
+
+	var urlErr *url.Error
+	var netErr net.Error
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	var authorityErr x509.UnknownAuthorityError
+	var hostnameErr x509.HostnameError
+	var invalidErr x509.CertificateInvalidError
+	var headerErr tls.RecordHeaderError
+
+	switch {
+	case errors.As(err, &authorityErr), errors.As(err, &hostnameErr), errors.As(err, &invalidErr), errors.As(err, &headerErr),
+		// net/http replaces the record header error of a plain HTTP answer.
+		strings.Contains(err.Error(), "server gave HTTP response to HTTPS client"):
+		return &Error{Kind: ErrorTLS, Err: err}
+	case errors.Is(err, context.DeadlineExceeded), errors.As(err, &urlErr), errors.As(err, &netErr):
+		return &Error{Kind: ErrorConnectivity, Err: err}
+	case errors.As(err, &syntaxErr), errors.As(err, &typeErr):
+		return &Error{Kind: ErrorMalformedResponse, Err: err}
+	}
+
+	message := strings.ToLower(err.Error())
+	for _, authMessage := range authMessages {
+		if strings.Contains(message, authMessage) {
+			return &Error{Kind: ErrorAuth, Err: err}
+		}
+	}
+	for _, lockMessage := range lockMessages {
+		if strings.Contains(message, lockMessage) {
+			return &Error{Kind: ErrorLockContention, Err: err}
+		}
+	}
+
+	if changes != nil {
+		return &Error{Kind: ErrorCommitValidation, Path: errorPath(err.Error(), changes), Err: err}
+	}
+	return err
+}
+
+// classifyStatus classifies an error from an HTTP API answer with the given
+// status. Server errors the message does not explain otherwise are reported
+// as such, rather than as a rejected change.
+func classifyStatus(status int, err error, changes []map[string]any) error {
+	classified := classify(err, changes)
+
+	result := &Error{Kind: ErrorUnknown, Err: classified}
+	var vyosErr *Error
+	if errors.As(classified, &vyosErr) {
+		copied := *vyosErr
+		result = &copied
+	}
+	result.StatusCode = status
+
+	if status >= http.StatusInternalServerError && (result.Kind == ErrorCommitValidation || result.Kind == ErrorUnknown) {
+		result.Kind = ErrorServer
+		result.Path = nil
+	}
+	return result
+}
+
+// errorPath returns the path a commit error refers to, taken from the error
+// message or else from the only operation of changes.
+func errorPath(message string, changes []map[string]any) []string {
+	if match := errorPathPattern.FindStringSubmatch(message); match != nil {
+		if path, err := ParsePath(match[1]); err == nil {
+			return path
+		}
+		return strings.Fields(match[1])
+	}
+
+	if len(changes) == 1 {
+		if path, ok := changes[0]["path"].([]string); ok {
+			return path
+		}
+	}
+	return nil
+}
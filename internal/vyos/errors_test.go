@@ -0,0 +1,121 @@
+package vyos
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/TGNThump/terraform-provider-vyos/internal/vyostest"
+)
+
+func TestErrorKinds(t *testing.T) {
+	ctx := context.Background()
+
+	tests := map[string]struct {
+		setup func(server *vyostest.Server)
+		kind  ErrorKind
+		path  []string
+	}{
+		"lock": {
+			setup: func(server *vyostest.Server) {
+				server.FailNext("configure", http.StatusBadRequest, "Configuration is locked by another session")
+			},
+			kind: ErrorLockContention,
+		},
+		"commit": {
+			setup: func(server *vyostest.Server) {
+				server.FailNext("configure", http.StatusBadRequest, "[ interfaces ethernet eth0 address ]\nInvalid IP address\n\n[[interfaces ethernet eth0]] failed\nCommit failed\n")
+			},
+			kind: ErrorCommitValidation,
+			path: []string{"interfaces", "ethernet", "eth0", "address"},
+		},
+		"set": {
+			setup: func(server *vyostest.Server) {
+				server.FailNext("configure", http.StatusBadRequest, "Value validation failed\nSet failed\n")
+			},
+			kind: ErrorCommitValidation,
+			path: []string{"system", "host-name"},
+		},
+		"server": {
+			setup: func(server *vyostest.Server) {
+				server.FailNext("configure", http.StatusServiceUnavailable, "Service Unavailable")
+			},
+			kind: ErrorServer,
+		},
+		"connectivity": {
+			setup: func(server *vyostest.Server) { server.Close() },
+			kind:  ErrorConnectivity,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			vc, server := newTestConfig(t, true)
+			WithRetry(0, 0)(vc)
+			test.setup(server)
+
+			err := vc.Set(ctx, []string{"system", "host-name"}, "router")
+			if kind := KindOf(err); kind != test.kind {
+				t.Fatalf("expected a %s error, got %s: %v", test.kind, kind, err)
+			}
+
+			if err.(*Error).Path != nil || test.path != nil {
+				if path := err.(*Error).Path; !reflect.DeepEqual(path, test.path) {
+					t.Errorf("unexpected path: %#v, expected: %#v", path, test.path)
+				}
+			}
+		})
+	}
+}
+
+func TestAuthError(t *testing.T) {
+	server := vyostest.NewServer("key")
+	t.Cleanup(server.Close)
+	vc := New(NewApiBackend(http.DefaultClient, server.URL, "wrong"), true, "")
+
+	_, err := vc.Show(context.Background(), []string{})
+	if kind := KindOf(err); kind != ErrorAuth {
+		t.Errorf("expected an auth error, got %s: %v", kind, err)
+	}
+}
+
+func TestMalformedResponseError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+		_, _ = w.Write([]byte("<html><body>502 Bad Gateway</body></html>"))
+	}))
+	t.Cleanup(server.Close)
+	vc := New(NewApiBackend(http.DefaultClient, server.URL, "key"), true, "", WithRetry(0, 0))
+
+	_, err := vc.Show(context.Background(), []string{})
+	if kind := KindOf(err); kind != ErrorMalformedResponse {
+		t.Errorf("expected a malformed response error, got %s: %v", kind, err)
+	}
+}
+
+func TestTLSErrors(t *testing.T) {
+	tlsServer := httptest.NewTLSServer(http.NotFoundHandler())
+	t.Cleanup(tlsServer.Close)
+	plainServer := httptest.NewServer(http.NotFoundHandler())
+	t.Cleanup(plainServer.Close)
+
+	endpoints := map[string]string{
+		"unknown authority": tlsServer.URL,
+		"not TLS":           strings.Replace(plainServer.URL, "http://", "https://", 1),
+	}
+
+	for name, endpoint := range endpoints {
+		t.Run(name, func(t *testing.T) {
+			backend := NewApiBackend(http.DefaultClient, endpoint, "key")
+			vc := New(backend, true, "")
+
+			_, err := vc.Show(context.Background(), []string{})
+			if kind := KindOf(err); kind != ErrorTLS {
+				t.Errorf("expected a tls error, got %s: %v", kind, err)
+			}
+		})
+	}
+}
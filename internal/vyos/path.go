@@ -0,0 +1,98 @@
+package vyos
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// ParsePath splits a configuration path into its components, following the
+// quoting rules of the VyOS CLI so components may contain spaces:
+//
+//	system login user vyos full-name 'VyOS User'
+//	system login banner pre-login "Authorised \"access\" only"
+//
+// Single quotes keep everything up to the closing quote literally. Within
+// double quotes, and outside of quotes, a backslash escapes the next character.
+func ParsePath(path string) ([]string, error) {
+	components := []string{}
+
+	var current strings.Builder
+	inComponent := false
+	var quote rune
+	escaped := false
+
+	for _, r := range path {
+		switch {
+		case escaped:
+			current.WriteRune(r)
+			escaped = false
+		case quote == '\'':
+			if r == '\'' {
+				quote = 0
+			} else {
+				current.WriteRune(r)
+			}
+		case r == '\\':
+			escaped = true
+			inComponent = true
+		case quote == '"':
+			if r == '"' {
+				quote = 0
+			} else {
+				current.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote = r
+			inComponent = true
+		case unicode.IsSpace(r):
+			if inComponent {
+				components = append(components, current.String())
+				current.Reset()
+				inComponent = false
+			}
+		default:
+			current.WriteRune(r)
+			inComponent = true
+		}
+	}
+
+	if escaped {
+		return nil, fmt.Errorf("path ends with an unfinished escape")
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("path has an unterminated %c quote", quote)
+	}
+	if inComponent {
+		components = append(components, current.String())
+	}
+
+	return components, nil
+}
+
+// FormatPath joins path components into a path ParsePath splits back into the
+// same components, quoting only the components which need it.
+func FormatPath(components []string) string {
+	quoted := make([]string, len(components))
+	for i, component := range components {
+		quoted[i] = quoteComponent(component)
+	}
+	return strings.Join(quoted, " ")
+}
+
+func quoteComponent(component string) string {
+	if component == "" {
+		return "''"
+	}
+	if strings.IndexFunc(component, func(r rune) bool {
+		return unicode.IsSpace(r) || r == '\'' || r == '"' || r == '\\'
+	}) < 0 {
+		return component
+	}
+	if !strings.ContainsRune(component, '\'') {
+		return "'" + component + "'"
+	}
+
+	replacer := strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+	return `"` + replacer.Replace(component) + `"`
+}
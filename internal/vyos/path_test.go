@@ -0,0 +1,61 @@
+package vyos
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParsePath(t *testing.T) {
+	cases := []struct {
+		path     string
+		expected []string
+	}{
+		{"", []string{}},
+		{"firewall name TEST", []string{"firewall", "name", "TEST"}},
+		{"  firewall   name\tTEST ", []string{"firewall", "name", "TEST"}},
+		{"system login user vyos full-name 'VyOS User'", []string{"system", "login", "user", "vyos", "full-name", "VyOS User"}},
+		{`system login banner pre-login "Authorised \"access\" only"`, []string{"system", "login", "banner", "pre-login", `Authorised "access" only`}},
+		{`interfaces ethernet eth0 description It\'s\ mine`, []string{"interfaces", "ethernet", "eth0", "description", "It's mine"}},
+		{`description 'back\slash'`, []string{"description", `back\slash`}},
+		{"description ''", []string{"description", ""}},
+	}
+
+	for _, c := range cases {
+		components, err := ParsePath(c.path)
+		if err != nil {
+			t.Errorf("%q: unexpected error: %s", c.path, err)
+		} else if !reflect.DeepEqual(components, c.expected) {
+			t.Errorf("%q: unexpected components: %#v, expected: %#v", c.path, components, c.expected)
+		}
+	}
+
+	for _, path := range []string{`description 'unterminated`, `description "unterminated`, `description trailing\`} {
+		if _, err := ParsePath(path); err == nil {
+			t.Errorf("%q: expected an error", path)
+		}
+	}
+}
+
+func TestFormatPathRoundTrip(t *testing.T) {
+	cases := [][]string{
+		{"firewall", "name", "TEST"},
+		{"system", "login", "user", "vyos", "full-name", "VyOS User"},
+		{"description", "It's \"quoted\""},
+		{"description", `back\slash`},
+		{"description", ""},
+	}
+
+	for _, components := range cases {
+		path := FormatPath(components)
+		parsed, err := ParsePath(path)
+		if err != nil {
+			t.Errorf("%q: unexpected error: %s", path, err)
+		} else if !reflect.DeepEqual(parsed, components) {
+			t.Errorf("%q: unexpected components: %#v, expected: %#v", path, parsed, components)
+		}
+	}
+
+	if path := FormatPath([]string{"system", "login", "user", "vyos", "full-name", "VyOS User"}); path != "system login user vyos full-name 'VyOS User'" {
+		t.Errorf("unexpected path: %s", path)
+	}
+}
@@ -0,0 +1,100 @@
+package vyos
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net"
+	"syscall"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+const (
+	// DefaultMaxRetries is how many times a failed request is retried.
+	DefaultMaxRetries = 5
+	// DefaultRetryMaxWait is the longest wait between two attempts.
+	DefaultRetryMaxWait = 30 * time.Second
+	// DefaultRetryBaseWait is the wait before the first retry, doubled for
+	// every further attempt.
+	DefaultRetryBaseWait = 500 * time.Millisecond
+)
+
+// WithRetry retries requests which failed because the configuration was
+// locked, the router could not be reached or answered with a server error up
+// to maxRetries times, backing
+// off exponentially up to maxWait between attempts. Zero retries disables
+// retrying.
+func WithRetry(maxRetries int64, maxWait time.Duration) Option {
+	return func(vc *VyosConfig) {
+		vc.maxRetries = maxRetries
+		vc.retryMaxWait = maxWait
+	}
+}
+
+// retryable reports whether a request which failed with err can be sent
+// again. Idempotent requests can be sent twice without changing the outcome,
+// others are only retried when the router cannot have applied them, so a
+// commit is never applied twice.
+func retryable(idempotent bool, err error) bool {
+	switch KindOf(err) {
+	case ErrorLockContention:
+		return true
+	case ErrorConnectivity:
+		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			return false
+		}
+		return idempotent || notSent(err)
+	case ErrorServer:
+		// The router may have carried out the request before failing.
+		return idempotent
+	case ErrorMalformedResponse:
+		// A proxy in front of the API answers with an HTML error page while
+		// the API daemon restarts.
+		return idempotent
+	default:
+		return false
+	}
+}
+
+// notSent reports whether err means the request never reached the router.
+func notSent(err error) bool {
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && opErr.Op == "dial" {
+		return true
+	}
+	return errors.Is(err, syscall.ECONNREFUSED)
+}
+
+// retryWait returns the wait before the given retry, counting from zero,
+// using exponential backoff with jitter so parallel requests spread out.
+func (vc *VyosConfig) retryWait(retry int) time.Duration {
+	wait := vc.retryBaseWait << retry
+	if wait <= 0 || wait > vc.retryMaxWait {
+		wait = vc.retryMaxWait
+	}
+	half := wait / 2
+	return half + time.Duration(rand.Int63n(int64(half)+1))
+}
+
+// retry runs request, running it again while it fails with a retryable
+// error.
+func (vc *VyosConfig) retry(ctx context.Context, idempotent bool, request func() error) error {
+	for retry := 0; ; retry++ {
+		err := request()
+		if err == nil || int64(retry) >= vc.maxRetries || !retryable(idempotent, err) {
+			return err
+		}
+
+		wait := vc.retryWait(retry)
+		tflog.Warn(ctx, fmt.Sprintf("VyOS request failed, retrying in %s: %s", wait, err))
+
+		select {
+		case <-time.After(wait):
+		case <-ctx.Done():
+			return err
+		}
+	}
+}
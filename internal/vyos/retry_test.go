@@ -0,0 +1,113 @@
+package vyos
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestRetryOnLockContention(t *testing.T) {
+	vc, server := newTestConfig(t, true)
+	server.FailNext("configure", http.StatusBadRequest, "Configuration is locked")
+	server.FailNext("configure", http.StatusBadRequest, "Configuration is locked")
+
+	if err := vc.Set(context.Background(), []string{"system", "host-name"}, "router"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if count := server.RequestCount("configure"); count != 3 {
+		t.Errorf("expected three configure requests, got %d", count)
+	}
+}
+
+func TestRetryOnServerError(t *testing.T) {
+	vc, server := newTestConfig(t, true)
+	server.FailNext("retrieve", http.StatusBadGateway, "Bad Gateway")
+
+	if _, err := vc.Show(context.Background(), []string{"system"}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if count := server.RequestCount("retrieve"); count != 2 {
+		t.Errorf("expected two retrieve requests, got %d", count)
+	}
+
+	// The commit may have been applied before the error, so it is not sent
+	// again.
+	server.FailNext("configure", http.StatusInternalServerError, "Internal Server Error")
+	err := vc.Set(context.Background(), []string{"system", "host-name"}, "router")
+	if kind := KindOf(err); kind != ErrorServer {
+		t.Fatalf("expected a server-error error, got %s: %v", kind, err)
+	}
+	if count := server.RequestCount("configure"); count != 1 {
+		t.Errorf("expected a single configure request, got %d", count)
+	}
+}
+
+func TestRetryGivesUp(t *testing.T) {
+	vc, server := newTestConfig(t, true)
+	WithRetry(2, time.Millisecond)(vc)
+	for i := 0; i < 5; i++ {
+		server.FailNext("configure", http.StatusBadRequest, "Configuration is locked")
+	}
+
+	err := vc.Set(context.Background(), []string{"system", "host-name"}, "router")
+	if kind := KindOf(err); kind != ErrorLockContention {
+		t.Fatalf("expected a lock-contention error, got %s: %v", kind, err)
+	}
+	if count := server.RequestCount("configure"); count != 3 {
+		t.Errorf("expected three configure requests, got %d", count)
+	}
+}
+
+func TestRetryable(t *testing.T) {
+	dial := &Error{Kind: ErrorConnectivity, Err: &url.Error{Op: "Post", Err: &net.OpError{Op: "dial", Err: errors.New("connection refused")}}}
+	reset := &Error{Kind: ErrorConnectivity, Err: &url.Error{Op: "Post", Err: io.ErrUnexpectedEOF}}
+	timeout := &Error{Kind: ErrorConnectivity, Err: context.DeadlineExceeded}
+	malformed := &Error{Kind: ErrorMalformedResponse, Err: errors.New("invalid character '<'")}
+	rejected := &Error{Kind: ErrorCommitValidation, Err: errors.New("Commit failed")}
+	unavailable := &Error{Kind: ErrorServer, StatusCode: http.StatusServiceUnavailable, Err: errors.New("Service Unavailable")}
+	untrusted := &Error{Kind: ErrorTLS, Err: errors.New("x509: certificate signed by unknown authority")}
+
+	cases := []struct {
+		idempotent bool
+		err        error
+		expected   bool
+	}{
+		{false, dial, true},
+		{false, reset, false},
+		{true, reset, true},
+		{true, timeout, false},
+		{true, malformed, true},
+		{false, malformed, false},
+		{false, rejected, false},
+		{true, untrusted, false},
+		{true, unavailable, true},
+		{false, unavailable, false},
+		{false, errors.New("unknown"), false},
+	}
+
+	for _, c := range cases {
+		if actual := retryable(c.idempotent, c.err); actual != c.expected {
+			t.Errorf("retryable(%v, %v) = %v, expected %v", c.idempotent, c.err, actual, c.expected)
+		}
+	}
+}
+
+func TestRetryWait(t *testing.T) {
+	vc := New(nil, true, "", WithRetry(10, time.Second))
+
+	for retry := 0; retry < 10; retry++ {
+		wait := vc.retryWait(retry)
+		expected := DefaultRetryBaseWait << retry
+		if expected > time.Second {
+			expected = time.Second
+		}
+		if wait < expected/2 || wait > expected {
+			t.Errorf("retry %d waits %s, expected between %s and %s", retry, wait, expected/2, expected)
+		}
+	}
+}
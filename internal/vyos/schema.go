@@ -0,0 +1,485 @@
+package vyos
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"net/netip"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// schemaFiles are snapshots of the VyOS interface definitions, the XML files
+// of vyos-1x describing every configuration node, for each supported version.
+// They cover the commonly managed subtrees; nodes marked open are not
+// described any further and accept any configuration.
+//
+//go:embed schema/*.json
+var schemaFiles embed.FS
+
+// Schema is the configuration schema of a VyOS version.
+type Schema struct {
+	Version string      `json:"version"`
+	Root    *SchemaNode `json:"root"`
+}
+
+// SchemaNode describes a configuration node, as in the interface definitions.
+type SchemaNode struct {
+	// Type is "tag" for tag nodes, such as `interfaces ethernet`, "leaf" for
+	// leaf nodes, and empty for plain nodes.
+	Type string `json:"type,omitempty"`
+	// Multi is set on leaves which hold a list of values.
+	Multi bool `json:"multi,omitempty"`
+	// Valueless is set on leaves which are set without a value, such as
+	// `disable`.
+	Valueless bool `json:"valueless,omitempty"`
+	// Open is set on nodes whose children are not all described. Children
+	// which are described are still checked.
+	Open bool `json:"open,omitempty"`
+	// Children are the nodes under the node, or under each tag of a tag node.
+	Children map[string]*SchemaNode `json:"children,omitempty"`
+	// Constraint restricts the values of a leaf or the tags of a tag node.
+	Constraint *SchemaConstraint `json:"constraint,omitempty"`
+}
+
+// SchemaConstraint restricts a value, which is valid when it matches any of
+// the regular expressions, validators or numeric ranges.
+type SchemaConstraint struct {
+	Regex      []string `json:"regex,omitempty"`
+	Validators []string `json:"validators,omitempty"`
+	Ranges     []string `json:"ranges,omitempty"`
+	// Error is the message explaining which values are valid.
+	Error string `json:"error,omitempty"`
+}
+
+// SchemaError is a path or value a configuration schema does not allow.
+type SchemaError struct {
+	// Path is the path of the node or value at fault.
+	Path    []string
+	Message string
+}
+
+func (e *SchemaError) Error() string {
+	return FormatPath(e.Path) + ": " + e.Message
+}
+
+var (
+	schemasOnce sync.Once
+	schemas     []*Schema
+	schemasErr  error
+)
+
+// Schemas returns the embedded schema snapshots, oldest version first.
+func Schemas() ([]*Schema, error) {
+	schemasOnce.Do(func() {
+		files, err := schemaFiles.ReadDir("schema")
+		if err != nil {
+			schemasErr = err
+			return
+		}
+		for _, file := range files {
+			data, err := schemaFiles.ReadFile(path.Join("schema", file.Name()))
+			if err != nil {
+				schemasErr = err
+				return
+			}
+			schema := &Schema{}
+			if err := json.Unmarshal(data, schema); err != nil {
+				schemasErr = fmt.Errorf("%s: %w", file.Name(), err)
+				return
+			}
+			schemas = append(schemas, schema)
+		}
+		sort.Slice(schemas, func(i, j int) bool {
+			newer := schemaVersion(schemas[j])
+			return !schemaVersion(schemas[i]).AtLeast(newer.Major, newer.Minor)
+		})
+	})
+	return schemas, schemasErr
+}
+
+func schemaVersion(schema *Schema) Version {
+	version, _ := ParseVersion(schema.Version)
+	return version
+}
+
+// SchemasFor returns the schemas to check configuration for version against:
+// the newest snapshot not newer than the router, or every snapshot when the
+// version is not known, as configuration valid on any of them may be meant.
+func SchemasFor(version Version) ([]*Schema, error) {
+	all, err := Schemas()
+	if err != nil || len(all) == 0 {
+		return nil, err
+	}
+	if !version.Known() {
+		return all, nil
+	}
+
+	for i := len(all) - 1; i > 0; i-- {
+		if snapshot := schemaVersion(all[i]); version.AtLeast(snapshot.Major, snapshot.Minor) {
+			return all[i : i+1], nil
+		}
+	}
+	return all[:1], nil
+}
+
+// ValidateSchema checks a value for the configuration path against the
+// schemas for version. Configuration any of the schemas allows is valid,
+// otherwise the errors of the newest one are returned.
+func ValidateSchema(version Version, path []string, value any) ([]*SchemaError, error) {
+	schemas, err := SchemasFor(version)
+	if err != nil {
+		return nil, err
+	}
+
+	var errs []*SchemaError
+	for i := len(schemas) - 1; i >= 0; i-- {
+		schemaErrs := schemas[i].Validate(path, value)
+		if len(schemaErrs) == 0 {
+			return nil, nil
+		}
+		if errs == nil {
+			errs = schemaErrs
+		}
+	}
+	return errs, nil
+}
+
+// Validate checks value, the configuration to set at path, returning an error
+// for each node or value the schema does not allow. A nil value sets a
+// valueless node.
+func (s *Schema) Validate(path []string, value any) []*SchemaError {
+	value = Normalize(value)
+
+	node := s.Root
+	for i := 0; i < len(path); i++ {
+		child, err := node.child(path[:i], path[i])
+		if err != nil {
+			return []*SchemaError{err}
+		}
+		if child == nil {
+			return nil
+		}
+		if i+1 == len(path) {
+			return child.validate(path, value)
+		}
+
+		switch child.Type {
+		case "tag":
+			i++
+			if err := child.checkValue(path[:i+1], "tag"); err != nil {
+				return []*SchemaError{err}
+			}
+			if i+1 == len(path) {
+				return child.validateChildren(path, value)
+			}
+		case "leaf":
+			// The component after a leaf is its value, which must end the
+			// path and is set without a value.
+			i++
+			if child.Valueless {
+				return []*SchemaError{{path[:i+1], fmt.Sprintf("%s is a valueless node and takes no value", path[i-1])}}
+			}
+			if err := child.checkValue(path[:i+1], "value"); err != nil {
+				return []*SchemaError{err}
+			}
+			if i+1 < len(path) {
+				return []*SchemaError{{path[:i+2], fmt.Sprintf("%q is a value and cannot hold nodes", path[i])}}
+			}
+			if tree, ok := value.(map[string]any); !ok || len(tree) > 0 {
+				return []*SchemaError{{path, fmt.Sprintf("%q is a value and cannot hold configuration", path[i])}}
+			}
+			return nil
+		}
+		node = child
+	}
+	return node.validateChildren(path, value)
+}
+
+// child returns the schema of the node name under the node at path, nil when
+// the node is open and does not describe it.
+func (n *SchemaNode) child(path []string, name string) (*SchemaNode, *SchemaError) {
+	if child, ok := n.Children[name]; ok {
+		return child, nil
+	}
+	if n.Open {
+		return nil, nil
+	}
+
+	message := fmt.Sprintf("%q is not a configuration node", name)
+	if len(path) > 0 {
+		message += " under " + FormatPath(path)
+	}
+	if suggestion := n.suggest(name); suggestion != "" {
+		message += fmt.Sprintf(", did you mean %q?", suggestion)
+	} else {
+		message += ", expected one of " + strings.Join(n.childNames(), ", ")
+	}
+	return nil, &SchemaError{append(append([]string{}, path...), name), message}
+}
+
+func (n *SchemaNode) childNames() []string {
+	names := make([]string, 0, len(n.Children))
+	for name := range n.Children {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// suggest returns the child whose name is closest to a misspelt name, empty
+// when none is close.
+func (n *SchemaNode) suggest(name string) string {
+	best, bestDistance := "", 3
+	for _, candidate := range n.childNames() {
+		if distance := editDistance(name, candidate); distance < bestDistance {
+			best, bestDistance = candidate, distance
+		}
+	}
+	return best
+}
+
+// validate checks the configuration of the node at path.
+func (n *SchemaNode) validate(path []string, value any) []*SchemaError {
+	if n.Open && len(n.Children) == 0 {
+		return nil
+	}
+
+	switch n.Type {
+	case "leaf":
+		return n.validateLeaf(path, value)
+	case "tag":
+		tree, ok := value.(map[string]any)
+		if !ok {
+			return []*SchemaError{{path, fmt.Sprintf("%s is a tag node and must hold an object of %s entries", FormatPath(path), path[len(path)-1])}}
+		}
+		var errs []*SchemaError
+		for _, tag := range sortedKeys(tree) {
+			tagPath := joinPath(path, []string{tag})
+			if err := n.checkValue(tagPath, "tag"); err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			errs = append(errs, n.validateChildren(tagPath, tree[tag])...)
+		}
+		return errs
+	}
+	return n.validateChildren(path, value)
+}
+
+// validateChildren checks the nodes under the node, or under a tag of a tag
+// node, at path.
+func (n *SchemaNode) validateChildren(path []string, value any) []*SchemaError {
+	tree, ok := value.(map[string]any)
+	if !ok {
+		if n.Open {
+			return nil
+		}
+		return []*SchemaError{{path, fmt.Sprintf("%s is a node and must hold an object, not a value", FormatPath(path))}}
+	}
+
+	var errs []*SchemaError
+	for _, name := range sortedKeys(tree) {
+		child, err := n.child(path, name)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if child != nil {
+			errs = append(errs, child.validate(joinPath(path, []string{name}), tree[name])...)
+		}
+	}
+	return errs
+}
+
+// validateLeaf checks the value of the leaf at path.
+func (n *SchemaNode) validateLeaf(path []string, value any) []*SchemaError {
+	name := path[len(path)-1]
+
+	switch v := value.(type) {
+	case map[string]any:
+		if len(v) > 0 {
+			return []*SchemaError{{path, fmt.Sprintf("%s is a leaf and cannot hold nodes", name)}}
+		}
+		if !n.Valueless {
+			return []*SchemaError{{path, fmt.Sprintf("%s requires a value", name)}}
+		}
+		return nil
+	case []any:
+		if n.Valueless {
+			return []*SchemaError{{path, fmt.Sprintf("%s is a valueless node and takes no value", name)}}
+		}
+		if !n.Multi {
+			return []*SchemaError{{path, fmt.Sprintf("%s takes a single value, not a list", name)}}
+		}
+		var errs []*SchemaError
+		for _, item := range v {
+			item, ok := item.(string)
+			if !ok {
+				errs = append(errs, &SchemaError{path, fmt.Sprintf("%s must hold a list of values", name)})
+				continue
+			}
+			if err := n.checkValue(joinPath(path, []string{item}), "value"); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		return errs
+	case string:
+		if n.Valueless {
+			return []*SchemaError{{path, fmt.Sprintf("%s is a valueless node and takes no value", name)}}
+		}
+		if err := n.checkValue(joinPath(path, []string{v}), "value"); err != nil {
+			return []*SchemaError{err}
+		}
+	}
+	return nil
+}
+
+// checkValue checks the last component of path, a value or tag of the node,
+// against the constraint of the node.
+func (n *SchemaNode) checkValue(path []string, what string) *SchemaError {
+	value := path[len(path)-1]
+	if n.Constraint == nil || n.Constraint.Allows(value) {
+		return nil
+	}
+
+	message := fmt.Sprintf("%q is not a valid %s for %s", value, what, path[len(path)-2])
+	if n.Constraint.Error != "" {
+		message += ": " + n.Constraint.Error
+	}
+	return &SchemaError{path, message}
+}
+
+// Allows reports whether value satisfies the constraint.
+func (c *SchemaConstraint) Allows(value string) bool {
+	for _, pattern := range c.Regex {
+		if matched, err := regexp.MatchString("^(?:"+pattern+")$", value); err == nil && matched {
+			return true
+		}
+	}
+	for _, validator := range c.Validators {
+		if schemaValidators[validator] != nil && schemaValidators[validator](value) {
+			return true
+		}
+	}
+	for _, valueRange := range c.Ranges {
+		low, high, _ := strings.Cut(valueRange, "-")
+		number, err := strconv.ParseInt(value, 10, 64)
+		lowNumber, lowErr := strconv.ParseInt(low, 10, 64)
+		highNumber, highErr := strconv.ParseInt(high, 10, 64)
+		if err == nil && lowErr == nil && highErr == nil && number >= lowNumber && number <= highNumber {
+			return true
+		}
+	}
+	return false
+}
+
+// schemaValidators are the validators of the interface definitions the
+// snapshots use, by name.
+var schemaValidators = map[string]func(string) bool{
+	"ipv4-address": func(value string) bool {
+		address, err := netip.ParseAddr(value)
+		return err == nil && address.Is4()
+	},
+	"ipv6-address": func(value string) bool {
+		address, err := netip.ParseAddr(value)
+		return err == nil && address.Is6()
+	},
+	"ipv4-prefix": func(value string) bool {
+		prefix, err := netip.ParsePrefix(value)
+		return err == nil && prefix.Addr().Is4()
+	},
+	"ipv6-prefix": func(value string) bool {
+		prefix, err := netip.ParsePrefix(value)
+		return err == nil && prefix.Addr().Is6()
+	},
+	"ip-address": func(value string) bool {
+		_, err := netip.ParseAddr(value)
+		return err == nil
+	},
+	"ip-prefix": func(value string) bool {
+		_, err := netip.ParsePrefix(value)
+		return err == nil
+	},
+	"ip-host": func(value string) bool {
+		prefix, err := netip.ParsePrefix(value)
+		if err != nil {
+			return false
+		}
+		// A host address is not the network address of its prefix, except
+		// on point to point links.
+		return prefix.Addr() != prefix.Masked().Addr() || prefix.Bits() >= prefix.Addr().BitLen()-1
+	},
+	"ipv4-range": func(value string) bool {
+		return addressRange(value, netip.Addr.Is4)
+	},
+	"ipv6-range": func(value string) bool {
+		return addressRange(value, netip.Addr.Is6)
+	},
+	"mac-address": func(value string) bool {
+		return macPattern.MatchString(value)
+	},
+}
+
+// HasSchemaValidator reports whether the validator of the interface
+// definitions called name is supported in constraints.
+func HasSchemaValidator(name string) bool {
+	return schemaValidators[name] != nil
+}
+
+var macPattern = regexp.MustCompile(`^([0-9A-Fa-f]{2}:){5}[0-9A-Fa-f]{2}$`)
+
+// addressRange reports whether value is a range of addresses of one family,
+// such as `192.0.2.10-192.0.2.20`.
+func addressRange(value string, family func(netip.Addr) bool) bool {
+	first, last, ok := strings.Cut(value, "-")
+	if !ok {
+		return false
+	}
+	firstAddress, err := netip.ParseAddr(first)
+	if err != nil || !family(firstAddress) {
+		return false
+	}
+	lastAddress, err := netip.ParseAddr(last)
+	return err == nil && family(lastAddress) && !lastAddress.Less(firstAddress)
+}
+
+func sortedKeys(tree map[string]any) []string {
+	keys := make([]string, 0, len(tree))
+	for key := range tree {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// editDistance returns the Levenshtein distance between two names.
+func editDistance(a string, b string) int {
+	previous := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current := make([]int, len(b)+1)
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = previous[j-1] + cost
+			if previous[j]+1 < current[j] {
+				current[j] = previous[j] + 1
+			}
+			if current[j-1]+1 < current[j] {
+				current[j] = current[j-1] + 1
+			}
+		}
+		previous = current
+	}
+	return previous[len(b)]
+}
@@ -0,0 +1,169 @@
+package vyos
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSchemasFor(t *testing.T) {
+	all, err := Schemas()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 2 || all[0].Version != "1.3" || all[1].Version != "1.4" {
+		t.Fatalf("unexpected schemas: %v", all)
+	}
+
+	tests := map[string][]string{
+		"":                         {"1.3", "1.4"},
+		"1.2.6":                    {"1.3"},
+		"1.3.8":                    {"1.3"},
+		"1.4.0":                    {"1.4"},
+		"1.5-rolling-202410090007": {"1.4"},
+	}
+	for raw, expected := range tests {
+		version, _ := ParseVersion(raw)
+		schemas, err := SchemasFor(version)
+		if err != nil {
+			t.Fatal(err)
+		}
+		versions := []string{}
+		for _, schema := range schemas {
+			versions = append(versions, schema.Version)
+		}
+		if !reflect.DeepEqual(versions, expected) {
+			t.Errorf("%q: got schemas %v, expected %v", raw, versions, expected)
+		}
+	}
+}
+
+func TestValidateSchema(t *testing.T) {
+	v13, _ := ParseVersion("1.3.8")
+	v14, _ := ParseVersion("1.4.0")
+
+	tests := []struct {
+		name    string
+		version Version
+		path    string
+		value   any
+		errors  []string
+	}{
+		{
+			name:    "ruleset",
+			version: v14,
+			path:    "firewall ipv4 name WAN",
+			value: map[string]any{
+				"default-action": "drop",
+				"rule": map[string]any{
+					"10": map[string]any{"action": "accept", "state": []any{"established", "related"}, "log": nil},
+				},
+			},
+		},
+		{
+			name:    "misspelt path",
+			version: v13,
+			path:    "firewall nmae WAN",
+			errors:  []string{`firewall nmae: "nmae" is not a configuration node under firewall, did you mean "name"?`},
+		},
+		{
+			name:    "layout of another version",
+			version: v14,
+			path:    "firewall name WAN",
+			errors:  []string{`firewall name: "name" is not a configuration node under firewall, expected one of bridge, flowtable, global-options, group, ipv4, ipv6, zone`},
+		},
+		{
+			name:    "either version when unknown",
+			version: Version{},
+			path:    "firewall name WAN",
+			value:   map[string]any{"default-action": "drop"},
+		},
+		{
+			name:    "misspelt key in value",
+			version: v14,
+			path:    "firewall ipv4 name WAN",
+			value: map[string]any{
+				"rule": map[string]any{
+					"10": map[string]any{"actoin": "accept"},
+					"20": map[string]any{"action": "allow"},
+				},
+			},
+			errors: []string{
+				`firewall ipv4 name WAN rule 10 actoin: "actoin" is not a configuration node under firewall ipv4 name WAN rule 10, did you mean "action"?`,
+				`firewall ipv4 name WAN rule 20 action allow: "allow" is not a valid value for action: Action must be accept, continue, drop, jump, queue, reject, return or synproxy`,
+			},
+		},
+		{
+			name:    "tag out of range",
+			version: v14,
+			path:    "firewall ipv4 name WAN rule 0",
+			value:   map[string]any{"action": "drop"},
+			errors:  []string{`firewall ipv4 name WAN rule 0: "0" is not a valid tag for rule: Rule number must be between 1 and 999999`},
+		},
+		{
+			name:    "leaf values",
+			version: v14,
+			path:    "interfaces ethernet eth0",
+			value: map[string]any{
+				"address":     []any{"192.0.2.1/24", "2001:db8::1/64", "dhcp"},
+				"mtu":         1500.0,
+				"description": []any{"one", "two"},
+				"disable":     "yes",
+				"duplex":      map[string]any{},
+				"vif":         map[string]any{"10": map[string]any{"address": "192.0.2.300/24"}},
+			},
+			errors: []string{
+				`interfaces ethernet eth0 description: description takes a single value, not a list`,
+				`interfaces ethernet eth0 disable: disable is a valueless node and takes no value`,
+				`interfaces ethernet eth0 duplex: duplex requires a value`,
+				`interfaces ethernet eth0 vif 10 address 192.0.2.300/24: "192.0.2.300/24" is not a valid value for address: Address must be an IPv4 or IPv6 address with prefix length, dhcp or dhcpv6`,
+			},
+		},
+		{
+			name:    "value in path",
+			version: v14,
+			path:    "interfaces ethernet eth0 mtu 20000",
+			errors:  []string{`interfaces ethernet eth0 mtu 20000: "20000" is not a valid value for mtu: MTU must be between 68 and 16000`},
+		},
+		{
+			name:    "nodes under a value",
+			version: v14,
+			path:    "system host-name vyos extra",
+			errors:  []string{`system host-name vyos extra: "vyos" is a value and cannot hold nodes`},
+		},
+		{
+			name:    "open subtree",
+			version: v14,
+			path:    "protocols bgp",
+			value:   map[string]any{"system-as": "65000", "anything": map[string]any{"goes": "here"}},
+		},
+		{
+			name:    "open node with described children",
+			version: v14,
+			path:    "system",
+			value:   map[string]any{"host-name": "-vyos", "login": map[string]any{}},
+			errors:  []string{`system host-name -vyos: "-vyos" is not a valid value for host-name: Host name must start with a letter or digit and contain only letters, digits, hyphens and dots`},
+		},
+	}
+
+	for _, test := range tests {
+		path, err := ParsePath(test.path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		errs, err := ValidateSchema(test.version, path, test.value)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		messages := []string{}
+		for _, err := range errs {
+			messages = append(messages, err.Error())
+		}
+		if test.errors == nil {
+			test.errors = []string{}
+		}
+		if !reflect.DeepEqual(messages, test.errors) {
+			t.Errorf("%s: got errors\n%q\nexpected\n%q", test.name, messages, test.errors)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package vyos
+
+import (
+	"context"
+	"strings"
+)
+
+// RunShow runs an operational mode `show` command, such as `show interfaces`,
+// and returns its output. path holds the words after `show`.
+func (vc *VyosConfig) RunShow(ctx context.Context, path []string) (output string, err error) {
+	err = vc.retry(ctx, true, func() error {
+		output, err = vc.backend.Show(ctx, path)
+		return err
+	})
+	return output, err
+}
+
+// Interface is a row of `show interfaces` output.
+type Interface struct {
+	Name        string
+	Addresses   []string
+	State       string
+	Link        string
+	Description string
+}
+
+// ParseShowInterfaces parses the table printed by `show interfaces`:
+//
+//	Codes: S - State, L - Link, u - Up, D - Down, A - Admin Down
+//	Interface        IP Address                        S/L  Description
+//	---------        ----------                        ---  -----------
+//	eth0             192.0.2.10/24                     u/u  WAN
+//	                 2001:db8::10/64
+//	eth1             -                                 A/D
+func ParseShowInterfaces(output string) []Interface {
+	interfaces := []Interface{}
+	inTable := false
+
+	for _, line := range strings.Split(output, "\n") {
+		if !inTable {
+			inTable = strings.HasPrefix(line, "---")
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+
+		// Further addresses of the previous interface are indented.
+		if line[0] == ' ' || line[0] == '\t' {
+			if len(interfaces) > 0 && fields[0] != "-" {
+				last := &interfaces[len(interfaces)-1]
+				last.Addresses = append(last.Addresses, fields[0])
+			}
+			continue
+		}
+
+		iface := Interface{Name: fields[0], Addresses: []string{}}
+		if len(fields) > 1 && fields[1] != "-" {
+			iface.Addresses = append(iface.Addresses, fields[1])
+		}
+		if len(fields) > 2 {
+			state, link, _ := strings.Cut(fields[2], "/")
+			iface.State = state
+			iface.Link = link
+		}
+		if len(fields) > 3 {
+			iface.Description = strings.Join(fields[3:], " ")
+		}
+		interfaces = append(interfaces, iface)
+	}
+
+	return interfaces
+}
+
+// ParseShowVersion parses the `Label: value` lines printed by `show version`,
+// keyed by the label in snake case, such as `release_train`.
+func ParseShowVersion(output string) map[string]string {
+	version := map[string]string{}
+
+	for _, line := range strings.Split(output, "\n") {
+		label, value, found := strings.Cut(line, ":")
+		if !found {
+			continue
+		}
+		label = strings.TrimSpace(label)
+		if label == "" || strings.HasPrefix(line, " ") {
+			continue
+		}
+
+		key := strings.ToLower(strings.Join(strings.Fields(label), "_"))
+		version[key] = strings.TrimSpace(value)
+	}
+
+	return version
+}
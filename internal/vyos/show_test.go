@@ -0,0 +1,53 @@
+package vyos
+
+import (
+	"reflect"
+	"testing"
+)
+
+const testShowInterfaces = `Codes: S - State, L - Link, u - Up, D - Down, A - Admin Down
+Interface        IP Address                        S/L  Description
+---------        ----------                        ---  -----------
+eth0             192.0.2.10/24                     u/u  WAN uplink
+                 2001:db8::10/64
+eth1             -                                 A/D
+lo               127.0.0.1/8                       u/u
+                 ::1/128
+`
+
+const testShowVersion = `Version:          VyOS 1.4.0
+Release train:    sagitta
+
+Built by:         autobuild@vyos.net
+Built on:         Mon 01 Jan 2024 00:00 UTC
+
+Architecture:     x86_64
+Hardware vendor:  QEMU
+`
+
+func TestParseShowInterfaces(t *testing.T) {
+	interfaces := ParseShowInterfaces(testShowInterfaces)
+	expected := []Interface{
+		{Name: "eth0", Addresses: []string{"192.0.2.10/24", "2001:db8::10/64"}, State: "u", Link: "u", Description: "WAN uplink"},
+		{Name: "eth1", Addresses: []string{}, State: "A", Link: "D"},
+		{Name: "lo", Addresses: []string{"127.0.0.1/8", "::1/128"}, State: "u", Link: "u"},
+	}
+	if !reflect.DeepEqual(interfaces, expected) {
+		t.Errorf("unexpected interfaces: %#v, expected: %#v", interfaces, expected)
+	}
+}
+
+func TestParseShowVersion(t *testing.T) {
+	version := ParseShowVersion(testShowVersion)
+	expected := map[string]string{
+		"version":         "VyOS 1.4.0",
+		"release_train":   "sagitta",
+		"built_by":        "autobuild@vyos.net",
+		"built_on":        "Mon 01 Jan 2024 00:00 UTC",
+		"architecture":    "x86_64",
+		"hardware_vendor": "QEMU",
+	}
+	if !reflect.DeepEqual(version, expected) {
+		t.Errorf("unexpected version: %#v, expected: %#v", version, expected)
+	}
+}
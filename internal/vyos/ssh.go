@@ -0,0 +1,247 @@
+package vyos
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+
+	"golang.org/x/crypto/ssh"
+)
+
+const (
+	// cfgWrapper runs configuration mode commands from a script.
+	cfgWrapper = "/opt/vyatta/sbin/vyatta-cfg-cmd-wrapper"
+	// opWrapper runs operational mode commands.
+	opWrapper = "/opt/vyatta/bin/vyatta-op-cmd-wrapper"
+)
+
+// sshBackend drives the VyOS CLI through vbash over SSH, for routers without
+// the HTTP API enabled. It needs VyOS 1.4 or later to read the configuration
+// as JSON, and does not commit with commit-confirm.
+type sshBackend struct {
+	address string
+	config  *ssh.ClientConfig
+
+	mutex  sync.Mutex
+	client *ssh.Client
+}
+
+// NewSSHBackend returns a Backend running VyOS CLI commands over SSH on the
+// router at address, given as host:port.
+func NewSSHBackend(address string, config *ssh.ClientConfig) Backend {
+	return &sshBackend{address: address, config: config}
+}
+
+// errSSHCommitConfirm is returned when a commit-confirm is asked of the SSH
+// transport.
+var errSSHCommitConfirm = errors.New("commit-confirm is not supported by the ssh transport")
+
+func (b *sshBackend) requires() Feature {
+	return FeatureSSHTransport
+}
+
+// dial opens a new SSH connection to the router.
+func (b *sshBackend) dial(ctx context.Context) (*ssh.Client, error) {
+	dialer := net.Dialer{Timeout: b.config.Timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", b.address)
+	if err != nil {
+		return nil, err
+	}
+
+	clientConn, channels, requests, err := ssh.NewClientConn(conn, b.address, b.config)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return ssh.NewClient(clientConn, channels, requests), nil
+}
+
+// connect returns the shared SSH connection, opening it if needed.
+func (b *sshBackend) connect(ctx context.Context) (*ssh.Client, error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if b.client == nil {
+		client, err := b.dial(ctx)
+		if err != nil {
+			return nil, err
+		}
+		b.client = client
+	}
+	return b.client, nil
+}
+
+// disconnect drops a broken connection, so the next command reconnects.
+func (b *sshBackend) disconnect(client *ssh.Client) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if b.client == client {
+		b.client = nil
+	}
+	client.Close()
+}
+
+// run runs command on the router with stdin as its input and returns its
+// output. A command exiting with an error returns its output as the error.
+func (b *sshBackend) run(ctx context.Context, command string, stdin string) (string, error) {
+	client, err := b.connect(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	session, err := client.NewSession()
+	if err != nil {
+		b.disconnect(client)
+		// Nothing ran, so the command is as safe to retry as a failed dial.
+		return "", &net.OpError{Op: "dial", Net: "ssh", Err: err}
+	}
+	defer session.Close()
+
+	session.Stdin = strings.NewReader(stdin)
+
+	var output []byte
+	done := make(chan error, 1)
+	go func() {
+		var err error
+		output, err = session.CombinedOutput(command)
+		done <- err
+	}()
+
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		session.Close()
+		return "", ctx.Err()
+	}
+
+	if err != nil {
+		var exitErr *ssh.ExitError
+		if !errors.As(err, &exitErr) {
+			b.disconnect(client)
+			return "", &Error{Kind: ErrorConnectivity, Err: err}
+		}
+
+		message := strings.TrimSpace(string(output))
+		if message == "" {
+			message = err.Error()
+		}
+		return "", errors.New(message)
+	}
+	return string(output), nil
+}
+
+// runScript runs a vbash script in a configuration session.
+func (b *sshBackend) runScript(ctx context.Context, commands []string, changes []map[string]any) error {
+	script := []string{
+		"W=" + cfgWrapper,
+		"$W begin || exit 1",
+		"trap '$W end' EXIT",
+	}
+	for _, command := range commands {
+		script = append(script, "$W "+command+" || exit 1")
+	}
+
+	_, err := b.run(ctx, "/bin/vbash -s", strings.Join(script, "\n")+"\n")
+	return classifySSH(err, changes)
+}
+
+func (b *sshBackend) Retrieve(ctx context.Context) (map[string]any, error) {
+	output, err := b.run(ctx, opWrapper+" show configuration json", "")
+	if err != nil {
+		return nil, classifySSH(err, nil)
+	}
+
+	if strings.TrimSpace(output) == "" {
+		return nil, nil
+	}
+
+	var config map[string]any
+	if err := json.Unmarshal([]byte(output), &config); err != nil {
+		return nil, classifySSH(err, nil)
+	}
+	return config, nil
+}
+
+func (b *sshBackend) Configure(ctx context.Context, payload []map[string]any, confirmMinutes int64) error {
+	if confirmMinutes > 0 {
+		return errSSHCommitConfirm
+	}
+
+	commands := []string{}
+	for _, op := range payload {
+		words := []string{fmt.Sprint(op["op"])}
+		if path, ok := op["path"].([]string); ok {
+			words = append(words, path...)
+		}
+		if value, ok := op["value"].(string); ok && value != "" {
+			words = append(words, value)
+		}
+		commands = append(commands, shellQuote(words))
+	}
+
+	commands = append(commands, "commit")
+
+	return b.runScript(ctx, commands, payload)
+}
+
+func (b *sshBackend) Confirm(ctx context.Context) error {
+	return errSSHCommitConfirm
+}
+
+func (b *sshBackend) Save(ctx context.Context, file string) error {
+	command := "save"
+	if file != "" {
+		command += " " + shellQuote([]string{file})
+	}
+	return b.runScript(ctx, []string{command}, nil)
+}
+
+func (b *sshBackend) Show(ctx context.Context, path []string) (string, error) {
+	output, err := b.run(ctx, opWrapper+" "+shellQuote(append([]string{"show"}, path...)), "")
+	return output, classifySSH(err, nil)
+}
+
+func (b *sshBackend) Ping(ctx context.Context) error {
+	// A new connection is opened, as one established before a change can
+	// outlive a change which cuts off SSH.
+	client, err := b.dial(ctx)
+	if err != nil {
+		return classifySSH(err, nil)
+	}
+	defer client.Close()
+
+	session, err := client.NewSession()
+	if err != nil {
+		return &Error{Kind: ErrorConnectivity, SSH: true, Err: err}
+	}
+	defer session.Close()
+
+	return classifySSH(session.Run("true"), nil)
+}
+
+// classifySSH classifies err like classify, marking it as an error of the
+// SSH transport so it is explained in its terms.
+func classifySSH(err error, changes []map[string]any) error {
+	err = classify(err, changes)
+	vyosErr, ok := err.(*Error)
+	if !ok {
+		return err
+	}
+	copied := *vyosErr
+	copied.SSH = true
+	return &copied
+}
+
+// shellQuote quotes words for vbash, so they reach the command unchanged.
+func shellQuote(words []string) string {
+	quoted := make([]string, len(words))
+	for i, word := range words {
+		quoted[i] = "'" + strings.ReplaceAll(word, "'", `'\''`) + "'"
+	}
+	return strings.Join(quoted, " ")
+}
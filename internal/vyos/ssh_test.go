@@ -0,0 +1,137 @@
+package vyos
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/TGNThump/terraform-provider-vyos/internal/vyostest"
+	"golang.org/x/crypto/ssh"
+)
+
+func newSSHTestConfig(t *testing.T, password string) (*VyosConfig, *vyostest.Server) {
+	server := vyostest.NewServer("key")
+	t.Cleanup(server.Close)
+	address, hostKey := server.StartSSH("vyos", "secret")
+
+	backend := NewSSHBackend(address, &ssh.ClientConfig{
+		User:            "vyos",
+		Auth:            []ssh.AuthMethod{ssh.Password(password)},
+		HostKeyCallback: ssh.FixedHostKey(hostKey),
+		Timeout:         5 * time.Second,
+	})
+	vc := New(backend, false, "/config/test.boot")
+	vc.retryBaseWait = time.Millisecond
+	return vc, server
+}
+
+func TestSSHSetShowDelete(t *testing.T) {
+	ctx := context.Background()
+	vc, server := newSSHTestConfig(t, "secret")
+	server.SetMulti("address")
+	server.SetValueless("disable")
+
+	err := vc.Set(ctx, []string{"interfaces", "dummy", "dum0"}, map[string]any{
+		"address":     []any{"10.0.0.1/24", "10.0.1.1/24"},
+		"description": "it's quoted",
+		"disable":     map[string]any{},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	expected := map[string]any{
+		"address":     []any{"10.0.0.1/24", "10.0.1.1/24"},
+		"description": "it's quoted",
+		"disable":     map[string]any{},
+	}
+	value, err := vc.Show(ctx, []string{"interfaces", "dummy", "dum0"})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !reflect.DeepEqual(value, expected) {
+		t.Errorf("unexpected value: %#v, expected %#v", value, expected)
+	}
+
+	if saves := server.Saves(); !reflect.DeepEqual(saves, []string{"/config/test.boot"}) {
+		t.Errorf("unexpected saves: %v", saves)
+	}
+
+	if err := vc.Delete(ctx, []string{"interfaces", "dummy", "dum0"}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if value, _ := vc.Show(ctx, []string{"interfaces"}); value != nil {
+		t.Errorf("expected deleted path to be nil, got %v", value)
+	}
+}
+
+func TestSSHRunShow(t *testing.T) {
+	vc, server := newSSHTestConfig(t, "secret")
+	server.SetShowOutput("version", "Version:          VyOS 1.4.0\n")
+
+	output, err := vc.RunShow(context.Background(), []string{"version"})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if output != "Version:          VyOS 1.4.0\n" {
+		t.Errorf("unexpected output: %q", output)
+	}
+}
+
+func TestSSHCommitConfirm(t *testing.T) {
+	vc, server := newSSHTestConfig(t, "secret")
+	WithCommitConfirm(5)(vc)
+
+	err := vc.Set(context.Background(), []string{"system", "host-name"}, "router")
+	if !errors.Is(err, errSSHCommitConfirm) {
+		t.Fatalf("expected commit-confirm to be refused, got %v", err)
+	}
+	if count := server.RequestCount("configure"); count != 0 {
+		t.Errorf("expected no commit, got %d", count)
+	}
+}
+
+func TestSSHRequiresVyos14(t *testing.T) {
+	ctx := context.Background()
+	vc, server := newSSHTestConfig(t, "secret")
+	server.SetVersion("1.3.8")
+
+	if _, err := vc.Show(ctx, []string{"system"}); err == nil || !strings.Contains(err.Error(), "the ssh transport requires VyOS 1.4") {
+		t.Errorf("expected the version to be refused, got %v", err)
+	}
+	if err := vc.Set(ctx, []string{"system", "host-name"}, "router"); err == nil || !strings.Contains(err.Error(), "the ssh transport requires VyOS 1.4") {
+		t.Errorf("expected the version to be refused, got %v", err)
+	}
+	if count := server.RequestCount("configure"); count != 0 {
+		t.Errorf("expected no commit, got %d", count)
+	}
+}
+
+func TestSSHErrors(t *testing.T) {
+	ctx := context.Background()
+
+	vc, server := newSSHTestConfig(t, "secret")
+	WithRetry(0, 0)(vc)
+	server.FailNext("configure", http.StatusBadRequest, "[ system host-name ]\nInvalid host name\n\nCommit failed\n")
+
+	err := vc.Set(ctx, []string{"system", "host-name"}, "-")
+	if kind := KindOf(err); kind != ErrorCommitValidation {
+		t.Fatalf("expected a commit-validation error, got %s: %v", kind, err)
+	}
+	if path := err.(*Error).Path; !reflect.DeepEqual(path, []string{"system", "host-name"}) {
+		t.Errorf("unexpected path: %v", path)
+	}
+
+	vc, _ = newSSHTestConfig(t, "wrong")
+	_, err = vc.Show(ctx, []string{})
+	if KindOf(err) != ErrorAuth {
+		t.Errorf("expected an auth error, got %s: %v", KindOf(err), err)
+	}
+	if vyosErr, ok := err.(*Error); !ok || !vyosErr.SSH {
+		t.Errorf("expected an error of the ssh transport, got %#v", err)
+	}
+}
@@ -0,0 +1,237 @@
+package vyos
+
+import (
+	"reflect"
+	"strconv"
+)
+
+// Normalize converts a configuration tree into the canonical form used to
+// compare configuration, following the conventions of the VyOS config tree:
+//
+//   - numbers and booleans become strings, as VyOS stores every value as text
+//   - null becomes an empty node, which is how valueless nodes are returned
+//   - a single-element list becomes the bare value, as multi-value leaves with
+//     one value are returned as a string
+//   - repeated values of leaf lists are dropped, keeping the order of the
+//     list, which matters for leaves such as `system name-server`
+func Normalize(value any) any {
+	switch v := value.(type) {
+	case nil:
+		return map[string]any{}
+	case bool:
+		return strconv.FormatBool(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case map[string]any:
+		result := make(map[string]any, len(v))
+		for key, child := range v {
+			result[key] = Normalize(child)
+		}
+		return result
+	case []any:
+		return normalizeList(v)
+	default:
+		return v
+	}
+}
+
+func normalizeList(list []any) any {
+	normalized := make([]any, 0, len(list))
+	leaves := make([]string, 0, len(list))
+	for _, item := range list {
+		item = Normalize(item)
+		normalized = append(normalized, item)
+		if leaf, ok := item.(string); ok {
+			leaves = append(leaves, leaf)
+		}
+	}
+
+	// Lists of anything but leaf values are not part of the VyOS config tree,
+	// keep those as they are.
+	if len(leaves) != len(normalized) {
+		return normalized
+	}
+
+	seen := make(map[string]bool, len(leaves))
+	result := []any{}
+	for _, leaf := range leaves {
+		if !seen[leaf] {
+			seen[leaf] = true
+			result = append(result, leaf)
+		}
+	}
+	if len(result) == 1 {
+		return result[0]
+	}
+	return result
+}
+
+// Child returns the node name under node, nil when there is none. A leaf
+// holding name as its value has it as a valueless node, the way VyOS paths
+// such as `system login banner pre-login 'Authorised access only'` address
+// the value of a leaf.
+func Child(node any, name string) any {
+	switch v := node.(type) {
+	case map[string]any:
+		return v[name]
+	case string:
+		if v == name {
+			return map[string]any{}
+		}
+	case []any:
+		for _, value := range v {
+			if value == name {
+				return map[string]any{}
+			}
+		}
+	}
+	return nil
+}
+
+// unorderedLeaves are the names of the leaf lists whose order means nothing,
+// such as the members of firewall groups and the addresses of interfaces.
+// VyOS may return them in another order than they were set in. Every other
+// leaf list is taken to be ordered.
+var unorderedLeaves = map[string]bool{
+	"address":        true,
+	"interface":      true,
+	"listen-address": true,
+	"network":        true,
+	"port":           true,
+	"state":          true,
+}
+
+// Unordered reports whether the order of the values of the leaf list name
+// means nothing.
+func Unordered(name string) bool {
+	return unorderedLeaves[name]
+}
+
+// Equal reports whether two configuration trees are the same once normalized.
+// Leaf lists are compared in order, unless their order means nothing.
+func Equal(a any, b any) bool {
+	return equalTrees(Normalize(a), Normalize(b), "")
+}
+
+func equalTrees(a any, b any, name string) bool {
+	aTree, aIsTree := a.(map[string]any)
+	bTree, bIsTree := b.(map[string]any)
+	if aIsTree || bIsTree {
+		if !aIsTree || !bIsTree || len(aTree) != len(bTree) {
+			return false
+		}
+		for key, aChild := range aTree {
+			bChild, ok := bTree[key]
+			if !ok || !equalTrees(aChild, bChild, key) {
+				return false
+			}
+		}
+		return true
+	}
+
+	aList, aIsList := a.([]any)
+	bList, bIsList := b.([]any)
+	if aIsList && bIsList && len(aList) == len(bList) && Unordered(name) {
+		values := make(map[string]bool, len(aList))
+		for _, value := range aList {
+			if leaf, ok := value.(string); ok {
+				values[leaf] = true
+			}
+		}
+		for _, value := range bList {
+			if leaf, ok := value.(string); !ok || !values[leaf] {
+				return false
+			}
+		}
+		return true
+	}
+
+	return reflect.DeepEqual(a, b)
+}
+
+// Undeclared returns the nodes in value which are not present in declared,
+// or nil if there are none.
+func Undeclared(value any, declared any) any {
+	tree, ok := value.(map[string]any)
+	if !ok {
+		return nil
+	}
+	declaredTree, ok := declared.(map[string]any)
+	if !ok {
+		return nil
+	}
+
+	result := map[string]any{}
+	for key, child := range tree {
+		declaredChild, ok := declaredTree[key]
+		if !ok {
+			result[key] = child
+		} else if undeclared := Undeclared(child, declaredChild); undeclared != nil {
+			result[key] = undeclared
+		}
+	}
+
+	if len(result) == 0 {
+		return nil
+	}
+	return result
+}
+
+// WithoutDefaults removes the nodes in defaults from value, unless they are
+// also present in declared. A node is only removed while it still has the
+// value recorded in defaults, so later changes to it are reported.
+func WithoutDefaults(value any, defaults any, declared any) any {
+	tree, ok := value.(map[string]any)
+	if !ok {
+		return value
+	}
+	defaultsTree, ok := defaults.(map[string]any)
+	if !ok {
+		return value
+	}
+	declaredTree, _ := declared.(map[string]any)
+
+	result := make(map[string]any, len(tree))
+	for key, child := range tree {
+		defaultChild, isDefault := defaultsTree[key]
+		declaredChild, isDeclared := declaredTree[key]
+
+		switch {
+		case !isDefault:
+			result[key] = child
+		case isDeclared:
+			result[key] = WithoutDefaults(child, defaultChild, declaredChild)
+		case !equalTrees(Normalize(child), Normalize(defaultChild), key):
+			if changed := WithoutDefaults(child, defaultChild, nil); !isEmptyTree(changed) {
+				result[key] = changed
+			}
+		}
+	}
+	return result
+}
+
+func isEmptyTree(value any) bool {
+	tree, ok := value.(map[string]any)
+	return ok && len(tree) == 0
+}
+
+// Declared returns the nodes of value which are also present in declared,
+// leaving out nodes managed elsewhere.
+func Declared(value any, declared any) any {
+	declaredTree, ok := declared.(map[string]any)
+	if !ok {
+		return value
+	}
+	tree, ok := value.(map[string]any)
+	if !ok {
+		return value
+	}
+
+	result := map[string]any{}
+	for key, declaredChild := range declaredTree {
+		if child, ok := tree[key]; ok {
+			result[key] = Declared(child, declaredChild)
+		}
+	}
+	return result
+}
@@ -0,0 +1,100 @@
+package vyos
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func decode(t *testing.T, value string) any {
+	var result any
+	if err := json.Unmarshal([]byte(value), &result); err != nil {
+		t.Fatalf("invalid JSON %s: %s", value, err)
+	}
+	return result
+}
+
+func TestEqual(t *testing.T) {
+	cases := []struct {
+		name string
+		a, b string
+	}{
+		{"key order", `{"a":"1","b":"2"}`, `{"b":"2","a":"1"}`},
+		{"numbers", `{"mtu":1500}`, `{"mtu":"1500"}`},
+		{"single element list", `{"address":["10.0.0.1/24"]}`, `{"address":"10.0.0.1/24"}`},
+		{"unordered leaf list", `{"address":["b","a"]}`, `{"address":["a","b"]}`},
+		{"repeated values", `{"name-server":["a","b","a"]}`, `{"name-server":["a","b"]}`},
+		{"valueless node", `{"disable":null}`, `{"disable":{}}`},
+		{"nested", `{"rule":{"10":{"port":[22]}}}`, `{"rule":{"10":{"port":"22"}}}`},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if !Equal(decode(t, c.a), decode(t, c.b)) {
+				t.Errorf("expected %s to equal %s", c.a, c.b)
+			}
+		})
+	}
+
+	if Equal(decode(t, `{"a":"1"}`), decode(t, `{"a":"1","b":"2"}`)) {
+		t.Error("expected additional keys to be a difference")
+	}
+	if Equal(decode(t, `{"name-server":["b","a"]}`), decode(t, `{"name-server":["a","b"]}`)) {
+		t.Error("expected the order of an ordered leaf list to be a difference")
+	}
+}
+
+func TestChild(t *testing.T) {
+	banner := decode(t, `{"pre-login":"Authorised access only","post-login":["a","b"]}`)
+
+	cases := []struct {
+		node     any
+		name     string
+		expected any
+	}{
+		{banner, "pre-login", "Authorised access only"},
+		{banner, "motd", nil},
+		{"Authorised access only", "Authorised access only", map[string]any{}},
+		{"Authorised access only", "Welcome", nil},
+		{[]any{"a", "b"}, "b", map[string]any{}},
+		{[]any{"a", "b"}, "c", nil},
+	}
+
+	for _, c := range cases {
+		if child := Child(c.node, c.name); !reflect.DeepEqual(child, c.expected) {
+			t.Errorf("unexpected child %q of %v: %v, expected %v", c.name, c.node, child, c.expected)
+		}
+	}
+}
+
+func TestWithoutDefaults(t *testing.T) {
+	declared := decode(t, `{"address":"10.0.0.1/24","ip":{"arp-cache-timeout":"30"}}`)
+	value := decode(t, `{"address":"10.0.0.1/24","hw-id":"00:11:22:33:44:55","ip":{"arp-cache-timeout":"30","proxy-arp":{}}}`)
+
+	defaults := Undeclared(value, declared)
+	expected := decode(t, `{"hw-id":"00:11:22:33:44:55","ip":{"proxy-arp":{}}}`)
+	if !reflect.DeepEqual(defaults, expected) {
+		t.Fatalf("unexpected defaults: %v, expected %v", defaults, expected)
+	}
+
+	if result := WithoutDefaults(value, defaults, declared); !reflect.DeepEqual(result, declared) {
+		t.Errorf("unexpected result: %v, expected %v", result, declared)
+	}
+
+	// Declaring a value VyOS filled in makes it managed again.
+	declared = decode(t, `{"address":"10.0.0.1/24","hw-id":"00:11:22:33:44:55","ip":{"arp-cache-timeout":"30"}}`)
+	if result := WithoutDefaults(value, defaults, declared); !reflect.DeepEqual(result, declared) {
+		t.Errorf("unexpected result: %v, expected %v", result, declared)
+	}
+}
+
+func TestWithoutDefaultsReportsChanges(t *testing.T) {
+	declared := decode(t, `{"address":"10.0.0.1/24"}`)
+	defaults := decode(t, `{"hw-id":"00:11:22:33:44:55","ip":{"arp-cache-timeout":"30","proxy-arp":{}}}`)
+	value := decode(t, `{"address":"10.0.0.1/24","hw-id":"00:11:22:33:44:66","ip":{"arp-cache-timeout":"60","proxy-arp":{}}}`)
+
+	expected := decode(t, `{"address":"10.0.0.1/24","hw-id":"00:11:22:33:44:66","ip":{"arp-cache-timeout":"60"}}`)
+	if result := WithoutDefaults(value, defaults, declared); !reflect.DeepEqual(result, expected) {
+		t.Errorf("unexpected result: %v, expected %v", result, expected)
+	}
+}
@@ -0,0 +1,211 @@
+package vyos
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Version is the VyOS release running on the router.
+type Version struct {
+	// Raw is the version reported by the router, such as `1.3.8` or
+	// `1.5-rolling-202410090007`.
+	Raw   string
+	Major int
+	Minor int
+}
+
+var versionPattern = regexp.MustCompile(`(\d+)\.(\d+)`)
+
+// ParseVersion parses a version reported by the router, with or without the
+// leading `VyOS`.
+func ParseVersion(raw string) (Version, error) {
+	raw = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(raw), "VyOS"))
+
+	match := versionPattern.FindStringSubmatch(raw)
+	if match == nil || !strings.HasPrefix(raw, match[0]) {
+		return Version{}, fmt.Errorf("unrecognised VyOS version %q", raw)
+	}
+
+	major, _ := strconv.Atoi(match[1])
+	minor, _ := strconv.Atoi(match[2])
+	return Version{Raw: raw, Major: major, Minor: minor}, nil
+}
+
+// Known reports whether the version of the router was detected.
+func (v Version) Known() bool {
+	return v.Raw != ""
+}
+
+// AtLeast reports whether the router runs major.minor or later. Unknown
+// versions are assumed to be the latest, so detection failures do not turn
+// features off.
+func (v Version) AtLeast(major int, minor int) bool {
+	if !v.Known() {
+		return true
+	}
+	return v.Major > major || (v.Major == major && v.Minor >= minor)
+}
+
+func (v Version) String() string {
+	if !v.Known() {
+		return "unknown"
+	}
+	return v.Raw
+}
+
+// Feature is a capability only some VyOS versions have.
+type Feature struct {
+	Name  string
+	Major int
+	Minor int
+}
+
+var (
+	// FeatureCommitConfirm is `commit-confirm` and `confirm` through the API.
+	FeatureCommitConfirm = Feature{"commit-confirm", 1, 4}
+	// FeatureFirewallFamilies is the firewall layout with rulesets under
+	// `firewall ipv4 name` and `firewall ipv6 name`, instead of
+	// `firewall name` and `firewall ipv6-name`.
+	FeatureFirewallFamilies = Feature{"firewall ipv4 and ipv6 rulesets", 1, 4}
+	// FeatureFirewallInterfaceGroups is `firewall group interface-group`.
+	FeatureFirewallInterfaceGroups = Feature{"firewall interface groups", 1, 4}
+	// FeatureValuelessLog is the valueless `log` node of firewall and NAT
+	// rules, instead of `log enable`.
+	FeatureValuelessLog = Feature{"valueless rule logging", 1, 4}
+	// FeatureNatInterfaceNames is `inbound-interface name` and
+	// `outbound-interface name` in source and destination NAT rules, instead
+	// of a plain interface leaf.
+	FeatureNatInterfaceNames = Feature{"NAT interface names", 1, 4}
+	// FeatureStaticNat is `nat static`.
+	FeatureStaticNat = Feature{"static NAT", 1, 4}
+	// FeatureSSHTransport is reading the configuration over SSH with
+	// `show configuration json`, which VyOS 1.3 does not have.
+	FeatureSSHTransport = Feature{"the ssh transport", 1, 4}
+)
+
+// Supports reports whether the router has feature.
+func (v Version) Supports(feature Feature) bool {
+	return v.AtLeast(feature.Major, feature.Minor)
+}
+
+// Require returns an error explaining that feature is missing, nil when the
+// router has it.
+func (v Version) Require(feature Feature) error {
+	if v.Supports(feature) {
+		return nil
+	}
+	return fmt.Errorf("%s requires VyOS %d.%d or later, the router runs VyOS %s", feature.Name, feature.Major, feature.Minor, v)
+}
+
+// FirewallNamePath returns the path of the firewall ruleset name for family,
+// `ipv4` or `ipv6`, in the layout of the router version.
+func (v Version) FirewallNamePath(family string, name string) []string {
+	if v.Supports(FeatureFirewallFamilies) {
+		return []string{"firewall", family, "name", name}
+	}
+	if family == "ipv6" {
+		return []string{"firewall", "ipv6-name", name}
+	}
+	return []string{"firewall", "name", name}
+}
+
+// infoBackend is implemented by backends which can ask the router for its
+// version without running `show version`, such as the /info endpoint of the
+// HTTP API on VyOS 1.5 and later.
+type infoBackend interface {
+	Info(ctx context.Context) (string, error)
+}
+
+// versionedBackend is implemented by backends which only work with some
+// VyOS versions.
+type versionedBackend interface {
+	// requires returns the feature the router needs for the backend to work.
+	requires() Feature
+}
+
+// requireBackend returns an error when the router runs a version the backend
+// does not work with.
+func (vc *VyosConfig) requireBackend(ctx context.Context) error {
+	backend, ok := vc.backend.(versionedBackend)
+	if !ok {
+		return nil
+	}
+	return vc.Version(ctx).Require(backend.requires())
+}
+
+// DetectVersion returns the version of the router, asking it on first use.
+// A failed attempt is not remembered, so the next call asks again.
+func (vc *VyosConfig) DetectVersion(ctx context.Context) (Version, error) {
+	vc.versionMutex.Lock()
+	defer vc.versionMutex.Unlock()
+
+	if vc.versionDetected {
+		return vc.version, nil
+	}
+
+	version, err := vc.detectVersion(ctx)
+	if err != nil {
+		return Version{}, err
+	}
+	vc.version = version
+	vc.versionDetected = true
+	return version, nil
+}
+
+// Version returns the version of the router like DetectVersion, but unknown
+// when detection fails, which is taken to support every feature. Resources
+// whose configuration paths depend on the version use DetectVersion instead,
+// so they never guess a layout.
+func (vc *VyosConfig) Version(ctx context.Context) Version {
+	version, err := vc.DetectVersion(ctx)
+	if err != nil {
+		tflog.Warn(ctx, "Unable to detect the VyOS version, assuming the latest: "+err.Error())
+	}
+	return version
+}
+
+// detectVersion uses the backend's Info when available, falling back to
+// `show version`. Both are retried like any other read.
+func (vc *VyosConfig) detectVersion(ctx context.Context) (Version, error) {
+	var raw string
+	if backend, ok := vc.backend.(infoBackend); ok {
+		err := vc.retry(ctx, true, func() error {
+			var err error
+			raw, err = backend.Info(ctx)
+			return err
+		})
+		if err != nil && retryable(true, err) {
+			// The router did not answer, `show version` would not fare better.
+			return Version{}, err
+		}
+		if err != nil {
+			tflog.Debug(ctx, "VyOS version not available from /info, falling back to show version: "+err.Error())
+		}
+	}
+
+	if raw == "" {
+		var output string
+		err := vc.retry(ctx, true, func() error {
+			var err error
+			output, err = vc.backend.Show(ctx, []string{"version"})
+			return err
+		})
+		if err != nil {
+			return Version{}, err
+		}
+		raw = ParseShowVersion(output)["version"]
+	}
+
+	version, err := ParseVersion(raw)
+	if err != nil {
+		return Version{}, &Error{Kind: ErrorMalformedResponse, Err: err}
+	}
+
+	tflog.Info(ctx, "Detected VyOS "+version.String())
+	return version, nil
+}
@@ -0,0 +1,139 @@
+package vyos
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestParseVersion(t *testing.T) {
+	tests := []struct {
+		raw   string
+		major int
+		minor int
+	}{
+		{"1.3.8", 1, 3},
+		{"VyOS 1.4.0", 1, 4},
+		{"1.4-rolling-202301260317", 1, 4},
+		{"1.5-rolling-202410090007", 1, 5},
+		{"999.202401010000", 999, 202401010000},
+	}
+
+	for _, test := range tests {
+		version, err := ParseVersion(test.raw)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %s", test.raw, err)
+			continue
+		}
+		if version.Major != test.major || version.Minor != test.minor {
+			t.Errorf("%s: parsed as %d.%d, expected %d.%d", test.raw, version.Major, version.Minor, test.major, test.minor)
+		}
+	}
+
+	for _, raw := range []string{"", "equuleus", "rolling-1.4"} {
+		if _, err := ParseVersion(raw); err == nil {
+			t.Errorf("%q: expected an error", raw)
+		}
+	}
+}
+
+func TestVersionFeatures(t *testing.T) {
+	v13, _ := ParseVersion("1.3.8")
+	v14, _ := ParseVersion("1.4.0")
+
+	if err := v13.Require(FeatureCommitConfirm); err == nil || err.Error() != "commit-confirm requires VyOS 1.4 or later, the router runs VyOS 1.3.8" {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := v14.Require(FeatureCommitConfirm); err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+	if !(Version{}).Supports(FeatureCommitConfirm) {
+		t.Error("expected an unknown version to support every feature")
+	}
+
+	if path := v13.FirewallNamePath("ipv4", "WAN"); !reflect.DeepEqual(path, []string{"firewall", "name", "WAN"}) {
+		t.Errorf("unexpected path: %v", path)
+	}
+	if path := v13.FirewallNamePath("ipv6", "WAN"); !reflect.DeepEqual(path, []string{"firewall", "ipv6-name", "WAN"}) {
+		t.Errorf("unexpected path: %v", path)
+	}
+	if path := v14.FirewallNamePath("ipv6", "WAN"); !reflect.DeepEqual(path, []string{"firewall", "ipv6", "name", "WAN"}) {
+		t.Errorf("unexpected path: %v", path)
+	}
+}
+
+func TestDetectVersion(t *testing.T) {
+	ctx := context.Background()
+
+	vc, server := newTestConfig(t, true)
+	server.SetVersion("1.5-rolling-202410090007")
+	version, err := vc.DetectVersion(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if version.Raw != "1.5-rolling-202410090007" || vc.Version(ctx) != version {
+		t.Errorf("unexpected version: %#v", version)
+	}
+	if server.RequestCount("info") != 1 || server.RequestCount("show") != 0 {
+		t.Errorf("expected the version to come from /info")
+	}
+
+	// Older routers have no /info, so `show version` is used instead.
+	vc, server = newTestConfig(t, true)
+	server.SetVersion("1.3.8")
+	version, err = vc.DetectVersion(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if version.Major != 1 || version.Minor != 3 {
+		t.Errorf("unexpected version: %#v", version)
+	}
+	if server.RequestCount("show") != 1 {
+		t.Errorf("expected the version to come from show version")
+	}
+
+	vc, server = newTestConfig(t, true)
+	server.SetVersion("1.3.8")
+	server.FailNext("show", http.StatusBadRequest, "Invalid command: show version")
+	if _, err := vc.DetectVersion(ctx); err == nil {
+		t.Error("expected an error")
+	}
+}
+
+func TestVersionIsDetectedOnFirstUse(t *testing.T) {
+	ctx := context.Background()
+
+	vc, server := newTestConfig(t, true)
+	server.SetVersion("1.3.8")
+	for i := 0; i < 3; i++ {
+		if version := vc.Version(ctx); version.Major != 1 || version.Minor != 3 {
+			t.Errorf("unexpected version: %#v", version)
+		}
+	}
+	if count := server.RequestCount("show"); count != 1 {
+		t.Errorf("expected the version to be detected once, got %d", count)
+	}
+
+	// A transient failure is retried.
+	vc, server = newTestConfig(t, true)
+	server.SetVersion("1.3.8")
+	server.FailNext("show", http.StatusBadRequest, "Configuration is locked by another session")
+	if version := vc.Version(ctx); version.Major != 1 || version.Minor != 3 {
+		t.Errorf("unexpected version: %#v", version)
+	}
+
+	// A failed detection is not remembered, the next use asks again.
+	vc, server = newTestConfig(t, true)
+	server.SetVersion("1.3.8")
+	server.FailNext("show", http.StatusBadRequest, "Invalid command: show version")
+	if version := vc.Version(ctx); version.Known() {
+		t.Errorf("unexpected version: %#v", version)
+	}
+	if version := vc.Version(ctx); version.Major != 1 || version.Minor != 3 {
+		t.Errorf("unexpected version: %#v", version)
+	}
+	if count := server.RequestCount("show"); count != 2 {
+		t.Errorf("expected two attempts, got %d", count)
+	}
+}
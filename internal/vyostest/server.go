@@ -0,0 +1,548 @@
+// Package vyostest provides an in-memory stand-in for the VyOS HTTP API, and
+// optionally for the VyOS CLI over SSH, so the provider can be exercised
+// without a router.
+package vyostest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+)
+
+// Request is a single call received by the fake server.
+type Request struct {
+	Endpoint string
+	Payload  any
+}
+
+type failure struct {
+	status  int
+	message string
+}
+
+// Server emulates the subset of the VyOS HTTP API used by the provider. The
+// configuration is held as the same JSON tree `showConfig` returns.
+type Server struct {
+	URL string
+	Key string
+
+	httpServer *httptest.Server
+	mutex      sync.Mutex
+	config     map[string]any
+	multi      map[string]bool
+	leaves     map[string]bool
+	failures   map[string][]failure
+	requests   []Request
+	saves      []string
+	shows      map[string]string
+	version    string
+
+	// rollback holds the configuration to restore while a commit-confirm is
+	// waiting to be confirmed.
+	rollback    map[string]any
+	unreachable bool
+	cutOff      bool
+
+	// conns are the open connections, and established the connections
+	// which stay usable while new ones are refused.
+	conns        map[net.Conn]bool
+	established  map[net.Conn]bool
+	refuseNew    bool
+	refuseNewCut bool
+
+	sshListener net.Listener
+	valueless   map[string]bool
+}
+
+// NewServer starts a fake VyOS HTTP API accepting the given API key.
+func NewServer(key string) *Server {
+	s := &Server{
+		Key:       key,
+		config:    map[string]any{},
+		multi:     map[string]bool{},
+		leaves:    map[string]bool{},
+		failures:  map[string][]failure{},
+		shows:     map[string]string{},
+		version:   "1.4.0",
+		valueless: map[string]bool{},
+		conns:     map[net.Conn]bool{},
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/retrieve", s.handle("retrieve", s.retrieve))
+	mux.HandleFunc("/configure", s.handle("configure", s.configure))
+	mux.HandleFunc("/config-file", s.handle("config-file", s.configFile))
+	mux.HandleFunc("/show", s.handle("show", s.show))
+	mux.HandleFunc("/info", s.handleInfo)
+
+	s.httpServer = httptest.NewUnstartedServer(mux)
+	s.httpServer.Config.ConnState = s.trackConn
+	s.httpServer.Config.ConnContext = func(ctx context.Context, conn net.Conn) context.Context {
+		return context.WithValue(ctx, connKey{}, conn)
+	}
+	s.httpServer.Start()
+	s.URL = s.httpServer.URL
+	return s
+}
+
+// Close shuts down the server.
+func (s *Server) Close() {
+	s.httpServer.Close()
+	if s.sshListener != nil {
+		s.sshListener.Close()
+	}
+}
+
+// SetConfig replaces the running configuration.
+func (s *Server) SetConfig(config map[string]any) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.config = copyTree(config)
+}
+
+// Config returns a copy of the running configuration.
+func (s *Server) Config() map[string]any {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return copyTree(s.config)
+}
+
+// SetMulti marks leaf node names which hold a list of values, such as
+// `address` or `name-server`. Setting one of these appends instead of replacing.
+func (s *Server) SetMulti(names ...string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	for _, name := range names {
+		s.multi[name] = true
+	}
+}
+
+// SetLeaves marks node names which hold a single value, such as `host-name`
+// or `pre-login`. Like VyOS, a set or delete without a value whose path runs
+// through one of these, or through a multi-value leaf, takes the last path
+// component as the value of the leaf.
+func (s *Server) SetLeaves(names ...string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	for _, name := range names {
+		s.leaves[name] = true
+	}
+}
+
+// FailNext makes the next request to endpoint fail with the given HTTP status
+// and error message, without touching the configuration.
+func (s *Server) FailNext(endpoint string, status int, message string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.failures[endpoint] = append(s.failures[endpoint], failure{status, message})
+}
+
+// Requests returns every request received so far.
+func (s *Server) Requests() []Request {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return append([]Request{}, s.requests...)
+}
+
+// RequestCount returns the number of requests received for endpoint.
+func (s *Server) RequestCount(endpoint string) int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	count := 0
+	for _, req := range s.requests {
+		if req.Endpoint == endpoint {
+			count++
+		}
+	}
+	return count
+}
+
+// Saves returns the file of every save operation, "" for the default
+// startup configuration.
+func (s *Server) Saves() []string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return append([]string{}, s.saves...)
+}
+
+// SetShowOutput sets the output of an operational mode command, given as the
+// words after `show`, such as "interfaces".
+func (s *Server) SetShowOutput(command string, output string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.shows[command] = output
+}
+
+// SetVersion sets the VyOS version the server reports, through `show
+// version` and, for VyOS 1.5 and later, the /info endpoint. It defaults to
+// 1.4.0.
+func (s *Server) SetVersion(version string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.version = version
+}
+
+// SetUnreachable makes the server drop every connection without answering,
+// like a router which is no longer reachable.
+func (s *Server) SetUnreachable(unreachable bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.unreachable = unreachable
+}
+
+// CutOffAfterNextCommit makes the server unreachable once the next commit has
+// been applied, like a change which locks out the API.
+func (s *Server) CutOffAfterNextCommit() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.cutOff = true
+}
+
+// RefuseNewConnectionsAfterNextCommit makes the server drop new connections
+// once the next commit has been applied, while connections opened before keep
+// working, like a firewall change which only lets established traffic in.
+func (s *Server) RefuseNewConnectionsAfterNextCommit() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.refuseNewCut = true
+}
+
+type connKey struct{}
+
+func (s *Server) trackConn(conn net.Conn, state http.ConnState) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	switch state {
+	case http.StateNew:
+		s.conns[conn] = true
+	case http.StateHijacked, http.StateClosed:
+		delete(s.conns, conn)
+	}
+}
+
+// refused reports whether the connection of r is dropped.
+func (s *Server) refused(r *http.Request) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.unreachable {
+		return true
+	}
+	conn, _ := r.Context().Value(connKey{}).(net.Conn)
+	return s.refuseNew && !s.established[conn]
+}
+
+// PendingConfirm reports whether a commit-confirm is waiting to be confirmed.
+func (s *Server) PendingConfirm() bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.rollback != nil
+}
+
+// ExpireConfirm lets the commit-confirm timer run out, rolling back to the
+// configuration before the unconfirmed commit, which restores access.
+func (s *Server) ExpireConfirm() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.rollback != nil {
+		s.config = s.rollback
+		s.rollback = nil
+		s.unreachable = false
+		s.refuseNew = false
+	}
+}
+
+type apiError struct {
+	status  int
+	message string
+}
+
+func (e *apiError) Error() string {
+	return e.message
+}
+
+func badRequest(format string, args ...any) error {
+	return &apiError{http.StatusBadRequest, fmt.Sprintf(format, args...)}
+}
+
+type handlerFunc func(payload any) (any, error)
+
+func (s *Server) handle(endpoint string, handler handlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if s.refused(r) {
+			dropConnection(w)
+			return
+		}
+
+		if r.Method != http.MethodPost {
+			writeResponse(w, http.StatusMethodNotAllowed, nil, "Method Not Allowed")
+			return
+		}
+
+		if r.FormValue("key") != s.Key {
+			writeResponse(w, http.StatusUnauthorized, nil, "Valid API key is required")
+			return
+		}
+
+		var payload any
+		if err := json.Unmarshal([]byte(r.FormValue("data")), &payload); err != nil {
+			writeResponse(w, http.StatusBadRequest, nil, "Failed to parse JSON: "+err.Error())
+			return
+		}
+
+		data, err := s.serve(endpoint, handler, payload)
+		if err != nil {
+			status := http.StatusInternalServerError
+			if e, ok := err.(*apiError); ok {
+				status = e.status
+			}
+			writeResponse(w, status, nil, err.Error())
+			return
+		}
+		writeResponse(w, http.StatusOK, data, "")
+	}
+}
+
+// handleInfo answers the unauthenticated /info endpoint, served from VyOS 1.5.
+func (s *Server) handleInfo(w http.ResponseWriter, r *http.Request) {
+	s.mutex.Lock()
+	unreachable := s.unreachable
+	version := s.version
+	s.mutex.Unlock()
+	if unreachable {
+		dropConnection(w)
+		return
+	}
+
+	if r.Method != http.MethodGet {
+		writeResponse(w, http.StatusMethodNotAllowed, nil, "Method Not Allowed")
+		return
+	}
+
+	var major, minor int
+	if _, err := fmt.Sscanf(version, "%d.%d", &major, &minor); err != nil || (major == 1 && minor < 5) {
+		writeResponse(w, http.StatusNotFound, nil, "Not Found")
+		return
+	}
+
+	data, err := s.serve("info", func(payload any) (any, error) {
+		return map[string]any{"version": version, "hostname": "vyos", "banner": ""}, nil
+	}, nil)
+	if err != nil {
+		writeResponse(w, http.StatusInternalServerError, nil, err.Error())
+		return
+	}
+	writeResponse(w, http.StatusOK, data, "")
+}
+
+// serve records a request to endpoint and answers it with handler, unless a
+// failure is queued for the endpoint.
+func (s *Server) serve(endpoint string, handler handlerFunc, payload any) (any, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.requests = append(s.requests, Request{endpoint, payload})
+	if queued := s.failures[endpoint]; len(queued) > 0 {
+		s.failures[endpoint] = queued[1:]
+		return nil, &apiError{queued[0].status, queued[0].message}
+	}
+	return handler(payload)
+}
+
+func dropConnection(w http.ResponseWriter) {
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+	if conn, _, err := hijacker.Hijack(); err == nil {
+		conn.Close()
+	}
+}
+
+func writeResponse(w http.ResponseWriter, status int, data any, message string) {
+	body := map[string]any{
+		"success": message == "",
+		"data":    data,
+		"error":   nil,
+	}
+	if message != "" {
+		body["error"] = message
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+type operation struct {
+	Op          string   `json:"op"`
+	Path        []string `json:"path"`
+	Value       *string  `json:"value"`
+	File        string   `json:"file"`
+	ConfirmTime int      `json:"confirm_time"`
+}
+
+func decodeOperation(payload any) (operation, error) {
+	var op operation
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return op, badRequest("%s", err.Error())
+	}
+	if err := json.Unmarshal(data, &op); err != nil {
+		return op, badRequest("Invalid request: %s", err.Error())
+	}
+	return op, nil
+}
+
+func (s *Server) retrieve(payload any) (any, error) {
+	op, err := decodeOperation(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	node, found := lookup(s.config, op.Path)
+
+	switch op.Op {
+	case "showConfig":
+		if !found {
+			return nil, badRequest("Configuration under specified path is empty\n")
+		}
+		if tree, ok := node.(map[string]any); ok {
+			return copyTree(tree), nil
+		}
+		return map[string]any{op.Path[len(op.Path)-1]: copyValue(node)}, nil
+
+	case "exists":
+		return found, nil
+
+	case "returnValue":
+		if value, ok := node.(string); ok {
+			return value, nil
+		}
+		return nil, badRequest("Path [%s] is not a leaf node with a value", joinPath(op.Path))
+
+	case "returnValues":
+		switch value := node.(type) {
+		case nil:
+			return []any{}, nil
+		case string:
+			return []any{value}, nil
+		case []any:
+			return copyValue(value), nil
+		}
+		return nil, badRequest("Path [%s] is not a leaf node with values", joinPath(op.Path))
+	}
+
+	return nil, badRequest("\"%s\" is not a valid operation", op.Op)
+}
+
+func (s *Server) configure(payload any) (any, error) {
+	var ops []operation
+	if list, ok := payload.([]any); ok {
+		for _, item := range list {
+			op, err := decodeOperation(item)
+			if err != nil {
+				return nil, err
+			}
+			ops = append(ops, op)
+		}
+	} else {
+		op, err := decodeOperation(payload)
+		if err != nil {
+			return nil, err
+		}
+		ops = append(ops, op)
+	}
+
+	if len(ops) == 1 && ops[0].Op == "confirm" {
+		if s.rollback == nil {
+			return nil, badRequest("No confirmation required")
+		}
+		s.rollback = nil
+		return "Commit confirmed", nil
+	}
+
+	// Operations are applied to a copy so a failure anywhere in the batch
+	// leaves the running configuration untouched, like a failed commit.
+	candidate := copyTree(s.config)
+	confirm := false
+	for _, op := range ops {
+		confirm = confirm || op.ConfirmTime > 0
+
+		if len(op.Path) == 0 {
+			return nil, badRequest("Missing required field \"path\"")
+		}
+
+		path, value := s.leafValue(candidate, op.Path, op.Value)
+
+		var err error
+		switch op.Op {
+		case "set":
+			err = s.set(candidate, path, value)
+		case "delete":
+			err = deletePath(candidate, path, value)
+		default:
+			err = badRequest("\"%s\" is not a valid operation", op.Op)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if confirm && s.rollback == nil {
+		s.rollback = s.config
+	}
+	s.config = candidate
+
+	if s.cutOff {
+		s.cutOff = false
+		s.unreachable = true
+	}
+	if s.refuseNewCut {
+		s.refuseNewCut = false
+		s.refuseNew = true
+		s.established = map[net.Conn]bool{}
+		for conn := range s.conns {
+			s.established[conn] = true
+		}
+	}
+	return nil, nil
+}
+
+func (s *Server) configFile(payload any) (any, error) {
+	op, err := decodeOperation(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	switch op.Op {
+	case "save":
+		s.saves = append(s.saves, op.File)
+		return "", nil
+	}
+
+	return nil, badRequest("\"%s\" is not a valid operation", op.Op)
+}
+
+func (s *Server) show(payload any) (any, error) {
+	op, err := decodeOperation(payload)
+	if err != nil {
+		return nil, err
+	}
+	if op.Op != "show" {
+		return nil, badRequest("\"%s\" is not a valid operation", op.Op)
+	}
+
+	output, ok := s.shows[joinPath(op.Path)]
+	if !ok && joinPath(op.Path) == "version" {
+		return "Version:          VyOS " + s.version + "\n", nil
+	}
+	if !ok {
+		return nil, badRequest("Invalid command: show %s", joinPath(op.Path))
+	}
+	return output, nil
+}
@@ -0,0 +1,182 @@
+package vyostest
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/foltik/vyos-client-go/client"
+)
+
+func TestServerSetShowDelete(t *testing.T) {
+	ctx := context.Background()
+	server := NewServer("key")
+	defer server.Close()
+	c := client.New(server.URL, "key")
+
+	err := c.Config.Set(ctx, "firewall name TEST", map[string]any{
+		"default-action": "drop",
+		"rule": map[string]any{
+			"10": map[string]any{"action": "accept"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	value, err := c.Config.Show(ctx, "firewall name TEST")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	expected := map[string]any{
+		"default-action": "drop",
+		"rule": map[string]any{
+			"10": map[string]any{"action": "accept"},
+		},
+	}
+	if !reflect.DeepEqual(value, expected) {
+		t.Errorf("unexpected config: %v, expected: %v", value, expected)
+	}
+
+	if err := c.Config.Delete(ctx, "firewall name TEST"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if config := server.Config(); len(config) != 0 {
+		t.Errorf("expected empty config, got: %v", config)
+	}
+}
+
+func TestServerValuelessNode(t *testing.T) {
+	ctx := context.Background()
+	server := NewServer("key")
+	defer server.Close()
+	c := client.New(server.URL, "key")
+
+	if err := c.Config.Set(ctx, "service ssh disable-host-validation", map[string]any{}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	expected := map[string]any{
+		"service": map[string]any{
+			"ssh": map[string]any{"disable-host-validation": map[string]any{}},
+		},
+	}
+	if config := server.Config(); !reflect.DeepEqual(config, expected) {
+		t.Errorf("unexpected config: %v, expected: %v", config, expected)
+	}
+}
+
+func TestServerLeafValuePath(t *testing.T) {
+	ctx := context.Background()
+	server := NewServer("key")
+	defer server.Close()
+	server.SetLeaves("host-name")
+	c := client.New(server.URL, "key")
+
+	// The last component of a path through a leaf is its value.
+	if err := c.Config.Set(ctx, "system host-name vyos", map[string]any{}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	expected := map[string]any{"system": map[string]any{"host-name": "vyos"}}
+	if config := server.Config(); !reflect.DeepEqual(config, expected) {
+		t.Errorf("unexpected config: %v, expected: %v", config, expected)
+	}
+
+	// A leaf which already holds a value is recognised without SetLeaves.
+	server.SetConfig(map[string]any{"system": map[string]any{"domain-name": "example.com"}})
+	if err := c.Config.Set(ctx, "system domain-name example.net", map[string]any{}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	expected = map[string]any{"system": map[string]any{"domain-name": "example.net"}}
+	if config := server.Config(); !reflect.DeepEqual(config, expected) {
+		t.Errorf("unexpected config: %v, expected: %v", config, expected)
+	}
+
+	if err := c.Config.Delete(ctx, "system domain-name example.net"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if config := server.Config(); len(config) != 0 {
+		t.Errorf("expected empty config, got: %v", config)
+	}
+}
+
+func TestServerMultiValue(t *testing.T) {
+	ctx := context.Background()
+	server := NewServer("key")
+	defer server.Close()
+	server.SetMulti("name-server")
+	c := client.New(server.URL, "key")
+
+	if err := c.Config.Set(ctx, "system name-server", []any{"1.1.1.1", "1.0.0.1"}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	values, err := c.Request(ctx, "retrieve", map[string]any{
+		"op":   "returnValues",
+		"path": []string{"system", "name-server"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if expected := []any{"1.1.1.1", "1.0.0.1"}; !reflect.DeepEqual(values, expected) {
+		t.Errorf("unexpected values: %v, expected: %v", values, expected)
+	}
+
+	if err := c.Config.Delete(ctx, "system name-server", "1.1.1.1"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if value, _ := c.Config.Show(ctx, "system name-server"); value != "1.0.0.1" {
+		t.Errorf("unexpected value: %v", value)
+	}
+}
+
+func TestServerFailedBatchIsDiscarded(t *testing.T) {
+	ctx := context.Background()
+	server := NewServer("key")
+	defer server.Close()
+	c := client.New(server.URL, "key")
+
+	_, err := c.Request(ctx, "configure", []map[string]any{
+		{"op": "set", "path": []string{"system", "host-name"}, "value": "router"},
+		{"op": "delete", "path": []string{"system", "domain-name"}},
+	})
+	if err == nil {
+		t.Fatal("expected delete of missing node to fail")
+	}
+	if config := server.Config(); len(config) != 0 {
+		t.Errorf("expected failed batch to be discarded, got: %v", config)
+	}
+}
+
+func TestServerFailNext(t *testing.T) {
+	ctx := context.Background()
+	server := NewServer("key")
+	defer server.Close()
+	c := client.New(server.URL, "key")
+
+	server.FailNext("configure", http.StatusBadRequest, "Configuration is locked")
+
+	err := c.Config.Set(ctx, "system host-name", "router")
+	if err == nil || err.Error() != "Configuration is locked" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := c.Config.Set(ctx, "system host-name", "router"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if count := server.RequestCount("configure"); count != 2 {
+		t.Errorf("unexpected configure count: %d", count)
+	}
+}
+
+func TestServerRejectsInvalidKey(t *testing.T) {
+	server := NewServer("key")
+	defer server.Close()
+	c := client.New(server.URL, "wrong")
+
+	_, err := c.Config.Show(context.Background(), "system")
+	if err == nil || err.Error() != "Valid API key is required" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
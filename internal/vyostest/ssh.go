@@ -0,0 +1,326 @@
+package vyostest
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+
+	"golang.org/x/crypto/ssh"
+)
+
+const (
+	cfgWrapper = "/opt/vyatta/sbin/vyatta-cfg-cmd-wrapper"
+	opWrapper  = "/opt/vyatta/bin/vyatta-op-cmd-wrapper"
+)
+
+// StartSSH serves the router over SSH as well, for user logging in with
+// password or one of authorizedKeys. It emulates the vbash scripts driving
+// vyatta-cfg-cmd-wrapper and the vyatta-op-cmd-wrapper commands run by the SSH
+// backend, against the same configuration as the HTTP API. It returns the
+// address to connect to and the host key of the server.
+func (s *Server) StartSSH(user string, password string, authorizedKeys ...ssh.PublicKey) (string, ssh.PublicKey) {
+	_, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		panic(fmt.Sprintf("vyostest: generating host key: %v", err))
+	}
+	signer, err := ssh.NewSignerFromKey(private)
+	if err != nil {
+		panic(fmt.Sprintf("vyostest: generating host key: %v", err))
+	}
+
+	config := &ssh.ServerConfig{
+		PasswordCallback: func(conn ssh.ConnMetadata, given []byte) (*ssh.Permissions, error) {
+			if conn.User() == user && password != "" && string(given) == password {
+				return nil, nil
+			}
+			return nil, fmt.Errorf("password rejected for %s", conn.User())
+		},
+		PublicKeyCallback: func(conn ssh.ConnMetadata, key ssh.PublicKey) (*ssh.Permissions, error) {
+			for _, authorized := range authorizedKeys {
+				if conn.User() == user && bytes.Equal(key.Marshal(), authorized.Marshal()) {
+					return nil, nil
+				}
+			}
+			return nil, fmt.Errorf("public key rejected for %s", conn.User())
+		},
+	}
+	config.AddHostKey(signer)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		panic(fmt.Sprintf("vyostest: failed to listen: %v", err))
+	}
+
+	s.mutex.Lock()
+	s.sshListener = listener
+	s.mutex.Unlock()
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go s.serveSSH(conn, config)
+		}
+	}()
+
+	return listener.Addr().String(), signer.PublicKey()
+}
+
+// SetValueless marks node names which take no value, such as `disable`. The
+// SSH wrapper commands cannot tell a value from the last path component, so
+// `set ... disable` only creates a valueless node for the names marked here.
+func (s *Server) SetValueless(names ...string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	for _, name := range names {
+		s.valueless[name] = true
+	}
+}
+
+func (s *Server) serveSSH(conn net.Conn, config *ssh.ServerConfig) {
+	s.mutex.Lock()
+	unreachable := s.unreachable
+	s.mutex.Unlock()
+	if unreachable {
+		conn.Close()
+		return
+	}
+
+	serverConn, channels, requests, err := ssh.NewServerConn(conn, config)
+	if err != nil {
+		conn.Close()
+		return
+	}
+	defer serverConn.Close()
+	go ssh.DiscardRequests(requests)
+
+	for newChannel := range channels {
+		if newChannel.ChannelType() != "session" {
+			_ = newChannel.Reject(ssh.UnknownChannelType, "unknown channel type")
+			continue
+		}
+
+		channel, channelRequests, err := newChannel.Accept()
+		if err != nil {
+			continue
+		}
+		go s.serveSession(channel, channelRequests)
+	}
+}
+
+func (s *Server) serveSession(channel ssh.Channel, requests <-chan *ssh.Request) {
+	defer channel.Close()
+
+	for request := range requests {
+		if request.Type != "exec" {
+			if request.WantReply {
+				_ = request.Reply(false, nil)
+			}
+			continue
+		}
+
+		var exec struct{ Command string }
+		if err := ssh.Unmarshal(request.Payload, &exec); err != nil {
+			_ = request.Reply(false, nil)
+			return
+		}
+		_ = request.Reply(true, nil)
+
+		output, status := s.exec(exec.Command, channel)
+		_, _ = io.WriteString(channel, output)
+
+		exitStatus := make([]byte, 4)
+		binary.BigEndian.PutUint32(exitStatus, status)
+		_, _ = channel.SendRequest("exit-status", false, exitStatus)
+		return
+	}
+}
+
+// exec runs a command, returning its output and exit status.
+func (s *Server) exec(command string, stdin io.Reader) (string, uint32) {
+	words, err := splitWords(command)
+	if err != nil || len(words) == 0 {
+		return fmt.Sprintf("vbash: syntax error: %s\n", command), 2
+	}
+
+	switch {
+	case words[0] == "true":
+		return "", 0
+
+	case words[0] == "/bin/vbash" && len(words) == 2 && words[1] == "-s":
+		script, err := io.ReadAll(stdin)
+		if err != nil {
+			return err.Error(), 1
+		}
+		return s.runScript(string(script))
+
+	case words[0] == opWrapper && len(words) > 1 && words[1] == "show":
+		if len(words) == 4 && words[2] == "configuration" && words[3] == "json" {
+			config, err := s.serve("retrieve", s.retrieve, map[string]any{"op": "showConfig", "path": []any{}})
+			if err != nil {
+				return err.Error(), 1
+			}
+			data, _ := json.Marshal(config)
+			return string(data) + "\n", 0
+		}
+
+		output, err := s.serve("show", s.show, map[string]any{"op": "show", "path": toAny(words[2:])})
+		if err != nil {
+			return err.Error(), 1
+		}
+		return output.(string), 0
+	}
+
+	return fmt.Sprintf("vbash: %s: command not found\n", words[0]), 127
+}
+
+// runScript runs a vbash script calling vyatta-cfg-cmd-wrapper, which only
+// applies the changes on `commit`.
+func (s *Server) runScript(script string) (string, uint32) {
+	var ops []any
+	inSession := false
+
+	for _, line := range strings.Split(script, "\n") {
+		line = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(line), "|| exit 1"))
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "trap ") {
+			continue
+		}
+		if strings.HasPrefix(line, "W=") {
+			if line != "W="+cfgWrapper {
+				return "vbash: unexpected wrapper: " + line + "\n", 127
+			}
+			continue
+		}
+
+		words, err := splitWords(line)
+		if err != nil {
+			return fmt.Sprintf("vbash: syntax error: %s\n", err), 2
+		}
+		if words[0] != "$W" || len(words) < 2 {
+			return fmt.Sprintf("vbash: unexpected command: %s\n", line), 127
+		}
+
+		command, args := words[1], words[2:]
+		if command != "begin" && !inSession {
+			return "Cannot run " + command + " outside of a configuration session\n", 1
+		}
+
+		var cmdErr error
+		switch command {
+		case "begin":
+			inSession = true
+		case "end":
+			inSession = false
+		case "set":
+			ops = append(ops, s.scriptOperation("set", args))
+		case "delete":
+			ops = append(ops, s.scriptOperation("delete", args))
+		case "commit":
+			_, cmdErr = s.serve("configure", s.configure, ops)
+			ops = nil
+		case "save":
+			op := map[string]any{"op": "save"}
+			if len(args) > 0 {
+				op["file"] = args[0]
+			}
+			_, cmdErr = s.serve("config-file", s.configFile, op)
+		default:
+			return fmt.Sprintf("Invalid command: %s\n", command), 1
+		}
+
+		if cmdErr != nil {
+			return cmdErr.Error(), 1
+		}
+	}
+
+	return "", 0
+}
+
+// scriptOperation turns the words of a `set` or `delete` wrapper command into
+// an operation of the HTTP API, where the last word is the value unless it
+// names a node which takes no value or, for deletes, an existing node.
+func (s *Server) scriptOperation(op string, words []string) map[string]any {
+	if len(words) < 2 {
+		return map[string]any{"op": op, "path": toAny(words)}
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	last := words[len(words)-1]
+	_, exists := lookup(s.config, words)
+	if s.valueless[last] || (op == "delete" && exists) {
+		return map[string]any{"op": op, "path": toAny(words)}
+	}
+	return map[string]any{"op": op, "path": toAny(words[:len(words)-1]), "value": last}
+}
+
+func toAny(words []string) []any {
+	result := make([]any, len(words))
+	for i, word := range words {
+		result[i] = word
+	}
+	return result
+}
+
+// splitWords splits a shell command line into words, following the single
+// quote, double quote and backslash rules of the shell.
+func splitWords(line string) ([]string, error) {
+	words := []string{}
+
+	var current strings.Builder
+	inWord := false
+	var quote rune
+	escaped := false
+
+	for _, r := range line {
+		switch {
+		case escaped:
+			current.WriteRune(r)
+			escaped = false
+		case quote == '\'':
+			if r == '\'' {
+				quote = 0
+			} else {
+				current.WriteRune(r)
+			}
+		case r == '\\':
+			escaped = true
+			inWord = true
+		case quote == '"':
+			if r == '"' {
+				quote = 0
+			} else {
+				current.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote = r
+			inWord = true
+		case r == ' ' || r == '\t':
+			if inWord {
+				words = append(words, current.String())
+				current.Reset()
+				inWord = false
+			}
+		default:
+			current.WriteRune(r)
+			inWord = true
+		}
+	}
+
+	if escaped || quote != 0 {
+		return nil, fmt.Errorf("unterminated quote or escape in %q", line)
+	}
+	if inWord {
+		words = append(words, current.String())
+	}
+	return words, nil
+}
@@ -0,0 +1,193 @@
+package vyostest
+
+import (
+	"strings"
+)
+
+func joinPath(path []string) string {
+	return strings.Join(path, " ")
+}
+
+func copyTree(tree map[string]any) map[string]any {
+	return copyValue(tree).(map[string]any)
+}
+
+func copyValue(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		result := make(map[string]any, len(v))
+		for key, child := range v {
+			result[key] = copyValue(child)
+		}
+		return result
+	case []any:
+		result := make([]any, len(v))
+		for i, child := range v {
+			result[i] = copyValue(child)
+		}
+		return result
+	default:
+		return v
+	}
+}
+
+func lookup(tree map[string]any, path []string) (any, bool) {
+	var node any = tree
+	for _, component := range path {
+		parent, ok := node.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		if node, ok = parent[component]; !ok {
+			return nil, false
+		}
+	}
+	return node, true
+}
+
+// parentOf walks to the node holding the last path component, creating
+// intermediate nodes when create is set.
+func parentOf(tree map[string]any, path []string, create bool) (map[string]any, error) {
+	node := tree
+	for i, component := range path[:len(path)-1] {
+		child, ok := node[component]
+		if !ok {
+			if !create {
+				return nil, nil
+			}
+			child = map[string]any{}
+			node[component] = child
+		}
+		if node, ok = child.(map[string]any); !ok {
+			return nil, badRequest("Configuration path: [%s] is not valid\nSet failed", joinPath(path[:i+1]))
+		}
+	}
+	return node, nil
+}
+
+// leafValue returns the path and value an operation applies to. A path
+// without a value which runs through a leaf, such as
+// `system login banner pre-login 'Authorised access only'`, ends in the value
+// of the leaf.
+func (s *Server) leafValue(tree map[string]any, path []string, value *string) ([]string, *string) {
+	if (value != nil && *value != "") || len(path) < 2 {
+		return path, value
+	}
+
+	leaf := path[:len(path)-1]
+	name := leaf[len(leaf)-1]
+	existing, exists := lookup(tree, leaf)
+	switch existing.(type) {
+	case string, []any:
+	default:
+		if exists || (!s.leaves[name] && !s.multi[name]) {
+			return path, value
+		}
+	}
+	return leaf, &path[len(path)-1]
+}
+
+func (s *Server) set(tree map[string]any, path []string, value *string) error {
+	parent, err := parentOf(tree, path, true)
+	if err != nil {
+		return err
+	}
+
+	terminal := path[len(path)-1]
+	existing, exists := parent[terminal]
+
+	// Valueless nodes, such as `disable` or a new tag node.
+	if value == nil || *value == "" {
+		if !exists {
+			parent[terminal] = map[string]any{}
+		} else if _, ok := existing.(map[string]any); !ok {
+			return badRequest("Configuration path: [%s] requires a value\nSet failed", joinPath(path))
+		}
+		return nil
+	}
+
+	if tree, ok := existing.(map[string]any); ok && len(tree) > 0 {
+		return badRequest("Configuration path: [%s %s] is not valid\nSet failed", joinPath(path), *value)
+	}
+
+	if !s.multi[terminal] || !exists {
+		parent[terminal] = *value
+		return nil
+	}
+
+	values := leafValues(existing)
+	for _, v := range values {
+		if v == *value {
+			return nil
+		}
+	}
+	parent[terminal] = append(values, *value)
+	return nil
+}
+
+func deletePath(tree map[string]any, path []string, value *string) error {
+	parent, err := parentOf(tree, path, false)
+	if err != nil {
+		return err
+	}
+
+	terminal := path[len(path)-1]
+	existing, exists := parent[terminal]
+	if parent == nil || !exists {
+		return badRequest("Nothing to delete (the specified node does not exist)\n")
+	}
+
+	if value == nil || *value == "" {
+		delete(parent, terminal)
+	} else {
+		var remaining []any
+		found := false
+		for _, v := range leafValues(existing) {
+			if v == *value {
+				found = true
+			} else {
+				remaining = append(remaining, v)
+			}
+		}
+		if !found {
+			return badRequest("Nothing to delete (the specified value does not exist)\n")
+		}
+
+		switch len(remaining) {
+		case 0:
+			delete(parent, terminal)
+		case 1:
+			parent[terminal] = remaining[0]
+		default:
+			parent[terminal] = remaining
+		}
+	}
+
+	prune(tree, path[:len(path)-1])
+	return nil
+}
+
+// prune removes nodes along path which were left without children.
+func prune(tree map[string]any, path []string) {
+	if len(path) == 0 {
+		return
+	}
+	child, ok := tree[path[0]].(map[string]any)
+	if !ok {
+		return
+	}
+	prune(child, path[1:])
+	if len(child) == 0 {
+		delete(tree, path[0])
+	}
+}
+
+func leafValues(value any) []any {
+	switch v := value.(type) {
+	case string:
+		return []any{v}
+	case []any:
+		return append([]any{}, v...)
+	}
+	return nil
+}
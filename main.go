@@ -3,8 +3,11 @@ package main
 import (
 	"context"
 	"flag"
+	"fmt"
 	"log"
+	"os"
 
+	"github.com/TGNThump/terraform-provider-vyos/internal/export"
 	"github.com/TGNThump/terraform-provider-vyos/internal/provider"
 	"github.com/hashicorp/terraform-plugin-framework/providerserver"
 )
@@ -15,6 +18,9 @@ import (
 // ensure the documentation is formatted properly.
 //go:generate terraform fmt -recursive ./examples/
 
+// Generate the typed resources from the VyOS interface definitions chosen in tools/resourcegen/resources.json.
+//go:generate go run ./tools/resourcegen
+
 // Run the docs generation tool, check its repository for more information on how it works and how docs
 // can be customized.
 //go:generate go run github.com/hashicorp/terraform-plugin-docs/cmd/tfplugindocs
@@ -29,6 +35,18 @@ var (
 )
 
 func main() {
+	// `terraform-provider-vyos export` writes a live router out as Terraform
+	// configuration instead of serving the provider.
+	if len(os.Args) > 1 && os.Args[1] == "export" {
+		if err := export.Main(context.Background(), os.Args[2:], os.Stdout); err != nil {
+			if err != flag.ErrHelp {
+				fmt.Fprintln(os.Stderr, "Error: "+err.Error())
+			}
+			os.Exit(1)
+		}
+		return
+	}
+
 	var debug bool
 
 	flag.BoolVar(&debug, "debug", false, "set to true to run the provider with support for debuggers like delve")
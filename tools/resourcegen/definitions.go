@@ -0,0 +1,179 @@
+package main
+
+import (
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// definitionNode is a node of the VyOS interface definitions, merged from
+// every file which declares it.
+type definitionNode struct {
+	// Kind is `node`, `tagNode` or `leafNode`.
+	Kind      string
+	Name      string
+	Help      string
+	Multi     bool
+	Valueless bool
+	Default   string
+
+	Regex                  []string
+	Validators             []definitionValidator
+	ConstraintErrorMessage string
+
+	Children map[string]*definitionNode
+}
+
+type definitionValidator struct {
+	Name     string `xml:"name,attr"`
+	Argument string `xml:"argument,attr"`
+}
+
+// xmlNode is a `node`, `tagNode` or `leafNode` element as written in the
+// interface definitions.
+type xmlNode struct {
+	XMLName    xml.Name
+	Name       string `xml:"name,attr"`
+	Properties *struct {
+		Help       string    `xml:"help"`
+		Multi      *struct{} `xml:"multi"`
+		Valueless  *struct{} `xml:"valueless"`
+		Constraint *struct {
+			Regex      []string              `xml:"regex"`
+			Validators []definitionValidator `xml:"validator"`
+		} `xml:"constraint"`
+		ConstraintErrorMessage string `xml:"constraintErrorMessage"`
+	} `xml:"properties"`
+	DefaultValue *string `xml:"defaultValue"`
+	Children     *struct {
+		Nodes []xmlNode `xml:",any"`
+	} `xml:"children"`
+}
+
+var includePattern = regexp.MustCompile(`(?m)^[ \t]*#include <([^>]+)>[ \t]*$`)
+
+// loadDefinitions reads every `.xml.in` file in dir into a single tree,
+// expanding the `#include` lines vyos-1x uses to share fragments.
+func loadDefinitions(dir string) (*definitionNode, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.xml.in"))
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no interface definitions found in %s", dir)
+	}
+	sort.Strings(files)
+
+	root := &definitionNode{Kind: "node", Children: map[string]*definitionNode{}}
+	for _, file := range files {
+		text, err := expandIncludes(dir, file, 0)
+		if err != nil {
+			return nil, err
+		}
+
+		var definition struct {
+			Nodes []xmlNode `xml:",any"`
+		}
+		if err := xml.Unmarshal([]byte(text), &definition); err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+		for _, node := range definition.Nodes {
+			if err := root.merge(node); err != nil {
+				return nil, fmt.Errorf("%s: %w", file, err)
+			}
+		}
+	}
+	return root, nil
+}
+
+func expandIncludes(dir string, file string, depth int) (string, error) {
+	if depth > 10 {
+		return "", fmt.Errorf("%s: includes nested too deeply", file)
+	}
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return "", err
+	}
+
+	var includeErr error
+	text := includePattern.ReplaceAllStringFunc(string(data), func(line string) string {
+		included, err := expandIncludes(dir, filepath.Join(dir, includePattern.FindStringSubmatch(line)[1]), depth+1)
+		if err != nil && includeErr == nil {
+			includeErr = err
+		}
+		return included
+	})
+	return text, includeErr
+}
+
+// merge adds the element node to the children of n. Declaring a node again
+// adds to it, as vyos-1x does to set the default of an included leaf.
+func (n *definitionNode) merge(node xmlNode) error {
+	kind := node.XMLName.Local
+	if kind != "node" && kind != "tagNode" && kind != "leafNode" {
+		return fmt.Errorf("unexpected element <%s> under %s", kind, n.Name)
+	}
+
+	child, ok := n.Children[node.Name]
+	if !ok {
+		child = &definitionNode{Kind: kind, Name: node.Name, Children: map[string]*definitionNode{}}
+		n.Children[node.Name] = child
+	} else if child.Kind != kind {
+		return fmt.Errorf("%s is declared as both a %s and a %s", node.Name, child.Kind, kind)
+	}
+
+	if properties := node.Properties; properties != nil {
+		if properties.Help != "" {
+			child.Help = strings.TrimSpace(properties.Help)
+		}
+		child.Multi = child.Multi || properties.Multi != nil
+		child.Valueless = child.Valueless || properties.Valueless != nil
+		if properties.Constraint != nil {
+			child.Regex = append(child.Regex, properties.Constraint.Regex...)
+			child.Validators = append(child.Validators, properties.Constraint.Validators...)
+		}
+		if properties.ConstraintErrorMessage != "" {
+			child.ConstraintErrorMessage = properties.ConstraintErrorMessage
+		}
+	}
+	if node.DefaultValue != nil {
+		child.Default = strings.TrimSpace(*node.DefaultValue)
+	}
+
+	if node.Children != nil {
+		for _, grandchild := range node.Children.Nodes {
+			if err := child.merge(grandchild); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// lookup returns the node at path.
+func (n *definitionNode) lookup(path []string) (*definitionNode, error) {
+	node := n
+	for i, name := range path {
+		child, ok := node.Children[name]
+		if !ok {
+			return nil, fmt.Errorf("%s is not in the interface definitions", strings.Join(path[:i+1], " "))
+		}
+		node = child
+	}
+	return node, nil
+}
+
+// childNames returns the names of the children of n, sorted.
+func (n *definitionNode) childNames() []string {
+	names := make([]string, 0, len(n.Children))
+	for name := range n.Children {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
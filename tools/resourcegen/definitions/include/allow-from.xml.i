@@ -0,0 +1,28 @@
+<!-- include start from allow-from.xml.i -->
+<leafNode name="allow-from">
+  <properties>
+    <help>Always allow inbound connections from these systems</help>
+    <valueHelp>
+      <format>ipv4</format>
+      <description>Address to match against</description>
+    </valueHelp>
+    <valueHelp>
+      <format>ipv4net</format>
+      <description>Prefix to match against</description>
+    </valueHelp>
+    <valueHelp>
+      <format>ipv6</format>
+      <description>Address to match against</description>
+    </valueHelp>
+    <valueHelp>
+      <format>ipv6net</format>
+      <description>Prefix to match against</description>
+    </valueHelp>
+    <constraint>
+      <validator name="ip-address"/>
+      <validator name="ip-prefix"/>
+    </constraint>
+    <multi/>
+  </properties>
+</leafNode>
+<!-- include end -->
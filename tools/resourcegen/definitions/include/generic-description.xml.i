@@ -0,0 +1,15 @@
+<!-- include start from generic-description.xml.i -->
+<leafNode name="description">
+  <properties>
+    <help>Description</help>
+    <valueHelp>
+      <format>txt</format>
+      <description>Description</description>
+    </valueHelp>
+    <constraint>
+      <regex>[[:ascii:]]{0,256}</regex>
+    </constraint>
+    <constraintErrorMessage>Description too long (limit 256 characters)</constraintErrorMessage>
+  </properties>
+</leafNode>
+<!-- include end -->
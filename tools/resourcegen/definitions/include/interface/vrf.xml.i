@@ -0,0 +1,10 @@
+<!-- include start from interface/vrf.xml.i -->
+<leafNode name="vrf">
+  <properties>
+    <help>VRF instance name</help>
+    <completionHelp>
+      <path>vrf name</path>
+    </completionHelp>
+  </properties>
+</leafNode>
+<!-- include end -->
@@ -0,0 +1,306 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/TGNThump/terraform-provider-vyos/internal/vyos"
+)
+
+// resourceSpec chooses a subtree of the interface definitions to generate a
+// resource for.
+type resourceSpec struct {
+	// TypeName is the resource type without the provider prefix, such as
+	// `service_ssh`.
+	TypeName string `json:"type_name"`
+	// Path is the configuration path of the subtree, such as `service ssh`.
+	// When it is a tag node, each resource manages one of its tags.
+	Path string `json:"path"`
+	// Description replaces the help of the node as the resource description.
+	Description string `json:"description,omitempty"`
+}
+
+const generatedHeader = "// Code generated by tools/resourcegen from the VyOS interface definitions. DO NOT EDIT.\n\n"
+
+var attributeNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// generator writes the Go source of a resource.
+type generator struct {
+	spec    resourceSpec
+	imports map[string]bool
+	// warnings are the constraints which could not be turned into
+	// validators, left unchecked.
+	warnings []string
+}
+
+// generateResource returns the source of the resource chosen by spec.
+func generateResource(root *definitionNode, spec resourceSpec) ([]byte, []string, error) {
+	path, err := vyos.ParsePath(spec.Path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", spec.TypeName, err)
+	}
+	node, err := root.lookup(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", spec.TypeName, err)
+	}
+	if node.Kind == "leafNode" {
+		return nil, nil, fmt.Errorf("%s: %s is a leaf, resources are generated for nodes and tag nodes", spec.TypeName, spec.Path)
+	}
+
+	g := &generator{spec: spec, imports: map[string]bool{
+		"github.com/hashicorp/terraform-plugin-framework/resource":        true,
+		"github.com/hashicorp/terraform-plugin-framework/resource/schema": true,
+	}}
+
+	description := spec.Description
+	if description == "" {
+		description = node.Help
+	}
+
+	reserved := map[string]bool{"id": true, "timeouts": true}
+	if node.Kind == "tagNode" {
+		reserved["name"] = true
+	}
+
+	var body bytes.Buffer
+	fmt.Fprintf(&body, "func New%sResource() resource.Resource {\n", goName(spec.TypeName))
+	fmt.Fprintf(&body, "return &typedResource{definition: typedResourceDefinition{\n")
+	fmt.Fprintf(&body, "TypeName: %q,\n", spec.TypeName)
+	fmt.Fprintf(&body, "Description: %s,\n", strconv.Quote(description))
+	fmt.Fprintf(&body, "Path: %s,\n", goStrings(path))
+	if node.Kind == "tagNode" {
+		fmt.Fprintf(&body, "Tag: true,\n")
+		if validator := g.stringValidator(node, path); validator != "" {
+			g.imports["github.com/hashicorp/terraform-plugin-framework/schema/validator"] = true
+			fmt.Fprintf(&body, "TagValidators: []validator.String{%s},\n", validator)
+		}
+	}
+	attributes, err := g.attributes(node, path, reserved)
+	if err != nil {
+		return nil, nil, err
+	}
+	fmt.Fprintf(&body, "Attributes: %s,\n", attributes)
+	fmt.Fprintf(&body, "}}\n}\n")
+
+	var source bytes.Buffer
+	source.WriteString(generatedHeader)
+	source.WriteString("package provider\n\n")
+	source.WriteString(goImports(g.imports))
+	source.Write(body.Bytes())
+
+	formatted, err := format.Source(source.Bytes())
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: formatting generated code: %w\n%s", spec.TypeName, err, source.String())
+	}
+	return formatted, g.warnings, nil
+}
+
+// generateRegistry returns the source of the list of generated resources.
+func generateRegistry(specs []resourceSpec) ([]byte, error) {
+	var source bytes.Buffer
+	source.WriteString(generatedHeader)
+	source.WriteString("package provider\n\n")
+	source.WriteString(goImports(map[string]bool{"github.com/hashicorp/terraform-plugin-framework/resource": true}))
+	source.WriteString("// generatedResources are the resources generated from the VyOS interface definitions.\n")
+	source.WriteString("var generatedResources = []func() resource.Resource{\n")
+	for _, spec := range specs {
+		fmt.Fprintf(&source, "New%sResource,\n", goName(spec.TypeName))
+	}
+	source.WriteString("}\n")
+	return format.Source(source.Bytes())
+}
+
+// attributes returns the attributes of the children of node.
+func (g *generator) attributes(node *definitionNode, path []string, reserved map[string]bool) (string, error) {
+	var code strings.Builder
+	code.WriteString("map[string]schema.Attribute{\n")
+	for _, name := range node.childNames() {
+		child := node.Children[name]
+		childPath := append(append([]string{}, path...), name)
+
+		attributeName := strings.ReplaceAll(name, "-", "_")
+		if !attributeNamePattern.MatchString(attributeName) || strings.Contains(name, "_") {
+			return "", fmt.Errorf("%s: %s cannot be named as an attribute", g.spec.TypeName, strings.Join(childPath, " "))
+		}
+		if reserved[attributeName] {
+			return "", fmt.Errorf("%s: %s clashes with the %s attribute of generated resources", g.spec.TypeName, strings.Join(childPath, " "), attributeName)
+		}
+
+		attribute, err := g.attribute(child, childPath)
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(&code, "%q: %s,\n", attributeName, attribute)
+	}
+	code.WriteString("}")
+	return code.String(), nil
+}
+
+// attribute returns the attribute setting node.
+func (g *generator) attribute(node *definitionNode, path []string) (string, error) {
+	description := node.Help
+	if node.Default != "" {
+		description += fmt.Sprintf(". VyOS defaults to `%s` when not set", node.Default)
+	}
+	description = strconv.Quote(description)
+
+	switch node.Kind {
+	case "node":
+		attributes, err := g.attributes(node, path, nil)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("schema.SingleNestedAttribute{\nMarkdownDescription: %s,\nOptional: true,\nAttributes: %s,\n}", description, attributes), nil
+
+	case "tagNode":
+		attributes, err := g.attributes(node, path, nil)
+		if err != nil {
+			return "", err
+		}
+		g.imports["github.com/hashicorp/terraform-plugin-framework-validators/mapvalidator"] = true
+		g.imports["github.com/hashicorp/terraform-plugin-framework/schema/validator"] = true
+		validators := []string{"mapvalidator.SizeAtLeast(1)"}
+		if validator := g.stringValidator(node, path); validator != "" {
+			validators = append(validators, "mapvalidator.KeysAre("+validator+")")
+		}
+		return fmt.Sprintf("schema.MapNestedAttribute{\nMarkdownDescription: %s,\nOptional: true,\nNestedObject: schema.NestedAttributeObject{\nAttributes: %s,\n},\nValidators: []validator.Map{%s},\n}",
+			description, attributes, strings.Join(validators, ", ")), nil
+	}
+
+	if node.Valueless {
+		g.imports["github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"] = true
+		return fmt.Sprintf("schema.BoolAttribute{\nMarkdownDescription: %s,\nOptional: true,\nComputed: true,\nDefault: booldefault.StaticBool(false),\n}", description), nil
+	}
+
+	if node.Multi {
+		g.imports["github.com/hashicorp/terraform-plugin-framework-validators/setvalidator"] = true
+		g.imports["github.com/hashicorp/terraform-plugin-framework/schema/validator"] = true
+		g.imports["github.com/hashicorp/terraform-plugin-framework/types"] = true
+		validators := []string{"setvalidator.SizeAtLeast(1)"}
+		if validator := g.stringValidator(node, path); validator != "" {
+			validators = append(validators, "setvalidator.ValueStringsAre("+validator+")")
+		}
+		return fmt.Sprintf("schema.SetAttribute{\nMarkdownDescription: %s,\nOptional: true,\nElementType: types.StringType,\nValidators: []validator.Set{%s},\n}",
+			description, strings.Join(validators, ", ")), nil
+	}
+
+	// Leaves only constrained to a numeric range are numbers.
+	if low, high, ok := numericRange(node); ok {
+		g.imports["github.com/hashicorp/terraform-plugin-framework-validators/int64validator"] = true
+		g.imports["github.com/hashicorp/terraform-plugin-framework/schema/validator"] = true
+		return fmt.Sprintf("schema.Int64Attribute{\nMarkdownDescription: %s,\nOptional: true,\nValidators: []validator.Int64{int64validator.Between(%d, %d)},\n}", description, low, high), nil
+	}
+
+	validators := ""
+	if validator := g.stringValidator(node, path); validator != "" {
+		g.imports["github.com/hashicorp/terraform-plugin-framework/schema/validator"] = true
+		validators = fmt.Sprintf("Validators: []validator.String{%s},\n", validator)
+	}
+	return fmt.Sprintf("schema.StringAttribute{\nMarkdownDescription: %s,\nOptional: true,\n%s}", description, validators), nil
+}
+
+// stringValidator returns a constraintValidator checking the values of node,
+// or its tags, empty when it is unconstrained or uses validators which are
+// not supported.
+func (g *generator) stringValidator(node *definitionNode, path []string) string {
+	if len(node.Regex) == 0 && len(node.Validators) == 0 {
+		return ""
+	}
+
+	constraint := vyos.SchemaConstraint{Regex: node.Regex, Error: node.ConstraintErrorMessage}
+	for _, validator := range node.Validators {
+		if low, high, ok := rangeArgument(validator); ok {
+			constraint.Ranges = append(constraint.Ranges, fmt.Sprintf("%d-%d", low, high))
+		} else if vyos.HasSchemaValidator(validator.Name) && validator.Argument == "" {
+			constraint.Validators = append(constraint.Validators, validator.Name)
+		} else {
+			g.warnings = append(g.warnings, fmt.Sprintf("%s: %s uses the unsupported validator %s %s, its values are not checked",
+				g.spec.TypeName, strings.Join(path, " "), validator.Name, validator.Argument))
+			return ""
+		}
+	}
+
+	g.imports["github.com/TGNThump/terraform-provider-vyos/internal/vyos"] = true
+
+	var code strings.Builder
+	code.WriteString("constraintValidator{vyos.SchemaConstraint{")
+	if len(constraint.Regex) > 0 {
+		fmt.Fprintf(&code, "Regex: %s, ", goStrings(constraint.Regex))
+	}
+	if len(constraint.Validators) > 0 {
+		fmt.Fprintf(&code, "Validators: %s, ", goStrings(constraint.Validators))
+	}
+	if len(constraint.Ranges) > 0 {
+		fmt.Fprintf(&code, "Ranges: %s, ", goStrings(constraint.Ranges))
+	}
+	if constraint.Error != "" {
+		fmt.Fprintf(&code, "Error: %s", strconv.Quote(constraint.Error))
+	}
+	code.WriteString("}}")
+	return strings.Replace(code.String(), ", }}", "}}", 1)
+}
+
+var rangePattern = regexp.MustCompile(`^--range (\d+)-(\d+)$`)
+
+// rangeArgument returns the bounds of a `numeric --range low-high`
+// validator.
+func rangeArgument(validator definitionValidator) (int64, int64, bool) {
+	match := rangePattern.FindStringSubmatch(validator.Argument)
+	if validator.Name != "numeric" || match == nil {
+		return 0, 0, false
+	}
+	low, _ := strconv.ParseInt(match[1], 10, 64)
+	high, _ := strconv.ParseInt(match[2], 10, 64)
+	return low, high, true
+}
+
+// numericRange returns the bounds of a leaf whose only constraint is a
+// numeric range.
+func numericRange(node *definitionNode) (int64, int64, bool) {
+	if len(node.Regex) > 0 || len(node.Validators) != 1 {
+		return 0, 0, false
+	}
+	return rangeArgument(node.Validators[0])
+}
+
+// goName turns a type name such as `service_ssh` into `ServiceSsh`.
+func goName(typeName string) string {
+	var name strings.Builder
+	for _, part := range strings.Split(typeName, "_") {
+		if part != "" {
+			name.WriteString(strings.ToUpper(part[:1]) + part[1:])
+		}
+	}
+	return name.String()
+}
+
+func goStrings(values []string) string {
+	quoted := make([]string, len(values))
+	for i, value := range values {
+		quoted[i] = strconv.Quote(value)
+	}
+	return "[]string{" + strings.Join(quoted, ", ") + "}"
+}
+
+// goImports returns the import block for packages, with the standard
+// grouping of this repository.
+func goImports(packages map[string]bool) string {
+	sorted := make([]string, 0, len(packages))
+	for pkg := range packages {
+		sorted = append(sorted, pkg)
+	}
+	sort.Strings(sorted)
+
+	var code strings.Builder
+	code.WriteString("import (\n")
+	for _, pkg := range sorted {
+		fmt.Fprintf(&code, "%q\n", pkg)
+	}
+	code.WriteString(")\n\n")
+	return code.String()
+}
@@ -0,0 +1,83 @@
+// Command resourcegen generates typed resources from the VyOS interface
+// definitions, the XML files of vyos-1x describing every configuration node.
+// resources.json chooses the subtrees to generate resources for:
+//
+//	go run ./tools/resourcegen -definitions ../vyos-1x/interface-definitions
+//
+// The definitions directory next to it holds the definitions of the subtrees
+// generated so far, trimmed from vyos-1x, so the resources can be regenerated
+// without a vyos-1x checkout.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+func main() {
+	definitions := flag.String("definitions", "tools/resourcegen/definitions", "directory of VyOS interface definitions")
+	resources := flag.String("resources", "tools/resourcegen/resources.json", "JSON file choosing the resources to generate")
+	output := flag.String("output", "internal/provider", "directory to write the generated resources to")
+	flag.Parse()
+
+	if err := run(*definitions, *resources, *output); err != nil {
+		fmt.Fprintln(os.Stderr, "resourcegen: "+err.Error())
+		os.Exit(1)
+	}
+}
+
+func run(definitions string, resources string, output string) error {
+	files, warnings, err := generate(definitions, resources)
+	if err != nil {
+		return err
+	}
+	for _, warning := range warnings {
+		fmt.Fprintln(os.Stderr, "resourcegen: warning: "+warning)
+	}
+
+	for name, source := range files {
+		if err := os.WriteFile(filepath.Join(output, name), source, 0o644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// generate returns the source of the generated files by name.
+func generate(definitions string, resources string) (map[string][]byte, []string, error) {
+	root, err := loadDefinitions(definitions)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	data, err := os.ReadFile(resources)
+	if err != nil {
+		return nil, nil, err
+	}
+	var specs []resourceSpec
+	if err := json.Unmarshal(data, &specs); err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", resources, err)
+	}
+
+	files := map[string][]byte{}
+	var warnings []string
+	for _, spec := range specs {
+		source, specWarnings, err := generateResource(root, spec)
+		if err != nil {
+			return nil, nil, err
+		}
+		files[spec.TypeName+"_resource_gen.go"] = source
+		warnings = append(warnings, specWarnings...)
+	}
+
+	registry, err := generateRegistry(specs)
+	if err != nil {
+		return nil, nil, err
+	}
+	files["resources_gen.go"] = registry
+
+	return files, warnings, nil
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestGeneratedResourcesAreUpToDate(t *testing.T) {
+	files, _, err := generate("definitions", "resources.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, source := range files {
+		existing, err := os.ReadFile(filepath.Join("..", "..", "internal", "provider", name))
+		if err != nil {
+			t.Errorf("%s: %s, run go generate", name, err)
+			continue
+		}
+		if !bytes.Equal(existing, source) {
+			t.Errorf("%s is out of date, run go generate", name)
+		}
+	}
+}
+
+func TestLoadDefinitions(t *testing.T) {
+	root, err := loadDefinitions("definitions")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Included leaves are declared again to set their default.
+	port, err := root.lookup([]string{"service", "ssh", "port"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if port.Kind != "leafNode" || port.Default != "22" || port.Help != "Port number used by connection" {
+		t.Errorf("unexpected port: %+v", port)
+	}
+	if expected := []definitionValidator{{"numeric", "--range 1-65535"}}; !reflect.DeepEqual(port.Validators, expected) {
+		t.Errorf("unexpected validators: %v", port.Validators)
+	}
+
+	hostName, err := root.lookup([]string{"system", "static-host-mapping", "host-name"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hostName.Kind != "tagNode" || len(hostName.Regex) != 1 || !reflect.DeepEqual(hostName.childNames(), []string{"alias", "inet"}) {
+		t.Errorf("unexpected host-name: %+v", hostName)
+	}
+
+	if _, err := root.lookup([]string{"service", "telnet"}); err == nil {
+		t.Error("expected an error for a missing node")
+	}
+}